@@ -0,0 +1,81 @@
+package main
+
+import (
+	"discovery/bpsim"
+	"discovery/config"
+	"discovery/constdef"
+	"discovery/errors"
+	"discovery/fmt"
+	"flag"
+	"os"
+	"strings"
+)
+
+type arg struct {
+	Listen   string
+	Protocol string
+	ApiPath  string
+	Username string
+	Password string
+	Duration int
+	Result   string
+}
+
+func parseArg() (*arg, *errors.Error) {
+	listenPtr := flag.String("listen", "127.0.0.1:8443", "listen address")
+	protocolPtr := flag.String("protocol", "https", "http, https(self-signed certificate)")
+	apiPathPtr := flag.String("api", "etc:restapi/bp", "restapi json directory")
+	usernamePtr := flag.String("username", "admin", "login username")
+	passwordPtr := flag.String("password", "admin", "login password")
+	durationPtr := flag.Int("duration", 0, "test running time(sec)")
+	resultPtr := flag.String("result", "passed", "test result, passed, failed, error")
+
+	flag.Parse()
+
+	if len(*listenPtr) == 0 || len(*apiPathPtr) == 0 {
+		return nil, errors.New("Invalid -listen, -api arguments")
+	}
+
+	apiPath := *apiPathPtr
+	if !strings.HasPrefix(apiPath, "/") {
+		path, err := config.GetLoadPath(apiPath, []string{})
+		if err != nil {
+			return nil, err
+		}
+		apiPath = path
+	}
+
+	arg1 := arg{
+		Listen:   *listenPtr,
+		Protocol: strings.ToLower(strings.TrimSpace(*protocolPtr)),
+		ApiPath:  apiPath,
+		Username: *usernamePtr,
+		Password: *passwordPtr,
+		Duration: *durationPtr,
+		Result:   *resultPtr,
+	}
+
+	return &arg1, nil
+}
+
+func main() {
+	arg, err := parseArg()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "ERR:", err.ToString(constdef.DEBUG))
+		os.Exit(1)
+	}
+
+	config.Version()
+
+	server, err := bpsim.NewServer(arg.ApiPath, arg.Username, arg.Password, arg.Duration, arg.Result)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "ERR:", err.ToString(constdef.DEBUG))
+		os.Exit(1)
+	}
+
+	err = server.ListenAndServe(arg.Listen, arg.Protocol)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "ERR:", err.ToString(constdef.DEBUG))
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,560 @@
+/* Breaking Point restful api 시뮬레이터
+ * etc/restapi/bp/*.json 의 method, urn 으로 route 생성
+ * 실제 chassis 없이 BPClient login, runtest, stoptest, export 테스트 용도
+ */
+package bpsim
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"discovery/errors"
+	"discovery/fmt"
+	"discovery/utils"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const SESSION_COOKIE_NAME = "bpsim_session"
+
+type route struct {
+	Command string
+	Method  string
+	Re      *regexp.Regexp
+	Params  []string
+	Payload map[string]interface{}
+}
+
+type request struct {
+	Params map[string]string
+	Body   map[string]interface{}
+}
+
+type simTest struct {
+	TestId    string
+	ModelName string
+	StartTime time.Time
+	Canceled  bool
+}
+
+type Server struct {
+	Username string
+	Password string
+	Duration int    /* test 실행 시간(초) */
+	Result   string /* 완료된 test 결과, passed, failed .. */
+
+	routes []*route
+
+	mutex      sync.Mutex
+	sessions   map[string]bool
+	reserved   map[string]bool /* slot/port */
+	template   string
+	components map[string]map[string]interface{}
+	tests      map[string]*simTest
+	lastTestId int
+}
+
+func NewServer(apiPath, username, password string, duration int, result string) (*Server, *errors.Error) {
+	if len(apiPath) == 0 || len(username) == 0 || len(password) == 0 {
+		return nil, errors.New("invalid arguments")
+	}
+
+	server := Server{
+		Username: username,
+		Password: password,
+		Duration: duration,
+		Result:   result,
+
+		sessions: make(map[string]bool),
+		reserved: make(map[string]bool),
+		components: map[string]map[string]interface{}{
+			"appsim_1":    {"name": "AppSim 1", "active": true},
+			"routing_1":   {"name": "Routing Robot 1", "active": true},
+			"bitblaster1": {"name": "BitBlaster 1", "active": true},
+		},
+		tests: make(map[string]*simTest),
+	}
+
+	err := server.loadRoutes(apiPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &server, nil
+}
+
+/* api json 파일로 route 생성
+ */
+func (self *Server) loadRoutes(apiPath string) *errors.Error {
+	paths, goerr := filepath.Glob(apiPath + "/*.json")
+	if goerr != nil {
+		return errors.New(fmt.Sprintf("%s", goerr))
+	}
+
+	if len(paths) == 0 {
+		return errors.New(fmt.Sprintf("%s doesn't have api json file", apiPath))
+	}
+	sort.Strings(paths)
+
+	paramRe := regexp.MustCompile(`{([A-Za-z_]+[A-Za-z0-9:_]*)}`)
+
+	for _, path := range paths {
+		command := strings.TrimSuffix(filepath.Base(path), ".json")
+		if _, ok := HANDLER_TABLE[command]; !ok {
+			return errors.New(fmt.Sprintf("'%s' api isn't supported", command))
+		}
+
+		ujson, err := utils.NewUjsonWithPath(path)
+		if err != nil {
+			return err
+		}
+
+		method, err := ujson.GetString("method")
+		if err != nil {
+			return err
+		}
+
+		/* download 는 GET 으로 요청
+		 */
+		method = strings.ToUpper(method)
+		if method == "DOWNLOAD" {
+			method = http.MethodGet
+		}
+
+		urn, err := ujson.GetString("urn")
+		if err != nil {
+			return err
+		}
+
+		params := []string{}
+		for _, match := range paramRe.FindAllStringSubmatch(urn, -1) {
+			params = append(params, match[1])
+		}
+		/* {runid} 같은 변수는 path 의 한 단계로 match
+		 */
+		pattern := regexp.QuoteMeta(paramRe.ReplaceAllString(urn, "\x00"))
+		pattern = "^" + strings.ReplaceAll(pattern, "\x00", "([^/]+)") + "$"
+
+		re, goerr := regexp.Compile(pattern)
+		if goerr != nil {
+			return errors.New(fmt.Sprintf("%s", goerr))
+		}
+
+		payload, _ := ujson.GetData("payload")
+
+		self.routes = append(self.routes, &route{
+			Command: command,
+			Method:  method,
+			Re:      re,
+			Params:  params,
+			Payload: payload,
+		})
+	}
+
+	return nil
+}
+
+/* method, urn 이 같은 api 는 payload 의 key 로 구분
+ */
+func (self *Server) findRoute(method, path string, body map[string]interface{}) (*route, map[string]string) {
+	var found *route
+	var foundParams map[string]string
+
+	for _, r := range self.routes {
+		if r.Method != method {
+			continue
+		}
+
+		match := r.Re.FindStringSubmatch(path)
+		if match == nil {
+			continue
+		}
+
+		params := make(map[string]string)
+		for i, name := range r.Params {
+			params[name] = match[i+1]
+		}
+
+		if found == nil {
+			found, foundParams = r, params
+		}
+
+		hasKeys := true
+		for key := range r.Payload {
+			if _, ok := body[key]; !ok {
+				hasKeys = false
+				break
+			}
+		}
+		if hasKeys && len(r.Payload) > 0 {
+			return r, params
+		}
+	}
+
+	return found, foundParams
+}
+
+func (self *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body := make(map[string]interface{})
+
+	data, goerr := io.ReadAll(r.Body)
+	if goerr == nil && len(strings.TrimSpace(string(data))) > 0 {
+		goerr = json.Unmarshal(data, &body)
+		if goerr != nil {
+			writeJson(w, http.StatusBadRequest, map[string]interface{}{"error": fmt.Sprintf("%s", goerr)})
+			return
+		}
+	}
+
+	route, params := self.findRoute(r.Method, r.URL.Path, body)
+	if route == nil {
+		fmt.Printf("%s %s -> not found\n", r.Method, r.URL.Path)
+		writeJson(w, http.StatusNotFound, map[string]interface{}{"error": "not found"})
+		return
+	}
+
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	/* login 외에는 session cookie 필요
+	 */
+	if route.Command != "login" {
+		cookie, goerr := r.Cookie(SESSION_COOKIE_NAME)
+		if goerr != nil || !self.sessions[cookie.Value] {
+			fmt.Printf("%s %s (%s) -> unauthorized\n", r.Method, r.URL.Path, route.Command)
+			writeJson(w, http.StatusUnauthorized, map[string]interface{}{"error": "session is not valid"})
+			return
+		}
+		params[SESSION_COOKIE_NAME] = cookie.Value
+	}
+
+	status, res := HANDLER_TABLE[route.Command](self, w, &request{Params: params, Body: body})
+	fmt.Printf("%s %s (%s) -> %d\n", r.Method, r.URL.Path, route.Command, status)
+
+	if data, ok := res.([]byte); ok {
+		w.Header().Set("Content-Type", "application/pdf")
+		w.WriteHeader(status)
+		w.Write(data)
+		return
+	}
+	writeJson(w, status, res)
+}
+
+func writeJson(w http.ResponseWriter, status int, res interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(res)
+}
+
+func errorRes(msg string) map[string]interface{} {
+	return map[string]interface{}{"error": msg}
+}
+
+type handler func(self *Server, w http.ResponseWriter, req *request) (int, interface{})
+
+var HANDLER_TABLE = map[string]handler{
+	"login":            (*Server).login,
+	"logout":           (*Server).logout,
+	"reserveports":     (*Server).reserveports,
+	"unreserveports":   (*Server).unreserveports,
+	"setnormaltest":    (*Server).setTemplate,
+	"setrfc":           (*Server).setTemplate,
+	"viewnormaltest":   (*Server).viewNormalTest,
+	"modifynormaltest": (*Server).modifyNormalTest,
+	"savenormaltest":   (*Server).saveNormalTest,
+	"runtest":          (*Server).runtest,
+	"getrts":           (*Server).getRTS,
+	"gettestresult":    (*Server).getTestResult,
+	"stoptest":         (*Server).stoptest,
+	"exporttestresult": (*Server).exportTestResult,
+}
+
+func (self *Server) login(w http.ResponseWriter, req *request) (int, interface{}) {
+	username, _ := req.Body["username"].(string)
+	password, _ := req.Body["password"].(string)
+
+	if username != self.Username || password != self.Password {
+		return http.StatusUnauthorized, errorRes("invalid username or password")
+	}
+
+	buf := make([]byte, 16)
+	rand.Read(buf)
+	session := hex.EncodeToString(buf)
+	self.sessions[session] = true
+
+	http.SetCookie(w, &http.Cookie{Name: SESSION_COOKIE_NAME, Value: session, Path: "/"})
+	return http.StatusOK, map[string]interface{}{"apiKey": session, "userName": username}
+}
+
+func (self *Server) logout(w http.ResponseWriter, req *request) (int, interface{}) {
+	delete(self.sessions, req.Params[SESSION_COOKIE_NAME])
+	return http.StatusNoContent, map[string]interface{}{}
+}
+
+func getPortKeys(body map[string]interface{}) ([]string, *errors.Error) {
+	slot, ok := body["slot"].(float64)
+	if !ok {
+		return nil, errors.New("slot is not set")
+	}
+
+	portList, ok := body["portList"].([]interface{})
+	if !ok || len(portList) == 0 {
+		return nil, errors.New("portList is not set")
+	}
+
+	keys := []string{}
+	for _, port := range portList {
+		p, ok := port.(float64)
+		if !ok {
+			return nil, errors.New(fmt.Sprintf("'%v' is invalid port", port))
+		}
+		keys = append(keys, fmt.Sprintf("%.0f/%.0f", slot, p))
+	}
+	return keys, nil
+}
+
+func (self *Server) reserveports(w http.ResponseWriter, req *request) (int, interface{}) {
+	keys, err := getPortKeys(req.Body)
+	if err != nil {
+		return http.StatusBadRequest, errorRes(err.ToString(false))
+	}
+
+	force, _ := req.Body["force"].(bool)
+	for _, key := range keys {
+		if self.reserved[key] && !force {
+			return http.StatusConflict, errorRes(fmt.Sprintf("%s port already reserved", key))
+		}
+	}
+
+	for _, key := range keys {
+		self.reserved[key] = true
+	}
+	return http.StatusOK, map[string]interface{}{}
+}
+
+func (self *Server) unreserveports(w http.ResponseWriter, req *request) (int, interface{}) {
+	keys, err := getPortKeys(req.Body)
+	if err != nil {
+		return http.StatusBadRequest, errorRes(err.ToString(false))
+	}
+
+	for _, key := range keys {
+		delete(self.reserved, key)
+	}
+	return http.StatusOK, map[string]interface{}{}
+}
+
+func (self *Server) setTemplate(w http.ResponseWriter, req *request) (int, interface{}) {
+	template, _ := req.Body["template"].(string)
+	if len(template) == 0 {
+		return http.StatusBadRequest, errorRes("template is not set")
+	}
+
+	self.template = template
+	return http.StatusNoContent, map[string]interface{}{}
+}
+
+func (self *Server) viewNormalTest(w http.ResponseWriter, req *request) (int, interface{}) {
+	if len(self.template) == 0 {
+		return http.StatusBadRequest, errorRes("working model is not set")
+	}
+	return http.StatusOK, self.components
+}
+
+func (self *Server) modifyNormalTest(w http.ResponseWriter, req *request) (int, interface{}) {
+	newParams, ok := req.Body["newParams"].(map[string]interface{})
+	if !ok {
+		return http.StatusBadRequest, errorRes("newParams is not set")
+	}
+
+	componentId, _ := newParams["componentId"].(string)
+	elementId, _ := newParams["elementId"].(string)
+
+	component, ok := self.components[componentId]
+	if !ok {
+		return http.StatusNotFound, errorRes(fmt.Sprintf("'%s' is invalid component id", componentId))
+	}
+
+	if len(elementId) == 0 {
+		return http.StatusBadRequest, errorRes("elementId is not set")
+	}
+
+	component[elementId] = newParams["value"]
+	return http.StatusNoContent, map[string]interface{}{}
+}
+
+func (self *Server) saveNormalTest(w http.ResponseWriter, req *request) (int, interface{}) {
+	name, _ := req.Body["name"].(string)
+	if len(name) == 0 {
+		return http.StatusBadRequest, errorRes("name is not set")
+	}
+	return http.StatusOK, map[string]interface{}{}
+}
+
+func (self *Server) runtest(w http.ResponseWriter, req *request) (int, interface{}) {
+	modelname, _ := req.Body["modelname"].(string)
+	if len(modelname) == 0 {
+		return http.StatusBadRequest, errorRes("modelname is not set")
+	}
+
+	if len(self.reserved) == 0 {
+		return http.StatusBadRequest, errorRes("ports are not reserved")
+	}
+
+	if modelname != self.template {
+		return http.StatusBadRequest, errorRes(fmt.Sprintf("'%s' is not working model", modelname))
+	}
+
+	self.lastTestId += 1
+	testid := fmt.Sprintf("TEST-%d", self.lastTestId)
+
+	self.tests[testid] = &simTest{
+		TestId:    testid,
+		ModelName: modelname,
+		StartTime: time.Now(),
+	}
+
+	return http.StatusOK, map[string]interface{}{"testid": testid}
+}
+
+func (self *Server) getTest(runid string) (*simTest, float64) {
+	test, ok := self.tests[runid]
+	if !ok {
+		return nil, 0
+	}
+
+	if self.Duration <= 0 {
+		return test, 100
+	}
+
+	progress := time.Since(test.StartTime).Seconds() * 100 / float64(self.Duration)
+	if progress > 100 {
+		progress = 100
+	}
+	return test, progress
+}
+
+func (self *Server) getRTS(w http.ResponseWriter, req *request) (int, interface{}) {
+	runid := fmt.Sprintf("%v", req.Body["runid"])
+
+	test, progress := self.getTest(runid)
+	if test == nil {
+		return http.StatusNotFound, errorRes(fmt.Sprintf("'%s' is invalid runid", runid))
+	}
+
+	return http.StatusOK, map[string]interface{}{"progress": progress}
+}
+
+func (self *Server) getTestResult(w http.ResponseWriter, req *request) (int, interface{}) {
+	runid := fmt.Sprintf("%v", req.Body["runid"])
+
+	test, progress := self.getTest(runid)
+	if test == nil {
+		return http.StatusNotFound, errorRes(fmt.Sprintf("'%s' is invalid runid", runid))
+	}
+
+	result := self.Result
+	if test.Canceled {
+		result = "canceled"
+	} else if progress < 100 {
+		result = "incomplete"
+	}
+
+	return http.StatusOK, map[string]interface{}{"result": result}
+}
+
+func (self *Server) stoptest(w http.ResponseWriter, req *request) (int, interface{}) {
+	testid := fmt.Sprintf("%v", req.Body["testid"])
+
+	test, progress := self.getTest(testid)
+	if test == nil {
+		return http.StatusNotFound, errorRes(fmt.Sprintf("'%s' is invalid testid", testid))
+	}
+
+	if progress < 100 {
+		test.Canceled = true
+	}
+	return http.StatusOK, map[string]interface{}{}
+}
+
+func (self *Server) exportTestResult(w http.ResponseWriter, req *request) (int, interface{}) {
+	runid := req.Params["runid"]
+
+	test, progress := self.getTest(runid)
+	if test == nil {
+		return http.StatusNotFound, errorRes(fmt.Sprintf("'%s' is invalid runid", runid))
+	}
+
+	report := fmt.Sprintf("%%PDF-1.4\n%% bpsim report\n%% testid: %s\n%% model: %s\n%% progress: %s\n%%%%EOF\n",
+		test.TestId, test.ModelName, strconv.FormatFloat(progress, 'f', -1, 64))
+	return http.StatusOK, []byte(report)
+}
+
+/* listen 주소에서 시뮬레이터 실행
+ * protocol 은 bp node rest_protocol 과 같이 http, https
+ * https 는 self-signed 인증서 사용, rest client 는 인증서 검증 안함
+ */
+func (self *Server) ListenAndServe(addr string, protocol string) *errors.Error {
+	fmt.Printf("bpsim listen %s://%s, %d api\n", protocol, addr, len(self.routes))
+
+	var goerr error
+	switch protocol {
+	case "http":
+		goerr = http.ListenAndServe(addr, self)
+	case "https":
+		cert, err := newSelfSignedCert()
+		if err != nil {
+			return err
+		}
+
+		httpServer := http.Server{
+			Addr:      addr,
+			Handler:   self,
+			TLSConfig: &tls.Config{Certificates: []tls.Certificate{cert}},
+		}
+		goerr = httpServer.ListenAndServeTLS("", "")
+	default:
+		return errors.New(fmt.Sprintf("'%s' is invalid protocol. http, https can available", protocol))
+	}
+
+	if goerr != nil {
+		return errors.New(fmt.Sprintf("%s", goerr))
+	}
+	return nil
+}
+
+func newSelfSignedCert() (tls.Certificate, *errors.Error) {
+	key, goerr := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if goerr != nil {
+		return tls.Certificate{}, errors.New(fmt.Sprintf("%s", goerr))
+	}
+
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "bpsim"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour * 24 * 365),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+
+	der, goerr := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if goerr != nil {
+		return tls.Certificate{}, errors.New(fmt.Sprintf("%s", goerr))
+	}
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, nil
+}
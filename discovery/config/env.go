@@ -5,6 +5,7 @@ import (
 	"discovery/constdef"
 	"discovery/errors"
 	"discovery/fmt"
+	"discovery/proc"
 	"discovery/utils"
 	"encoding/hex"
 	"reflect"
@@ -21,8 +22,19 @@ var NODE_TABLE = map[string]NodeInterface{
 	"rest":   &NodeRest{},
 	"docker": &NodeDockerContainer{},
 	"cisco":  &NodeCisco{},
+	"serial": &NodeSerial{},
+	"bp":     &NodeBP{},
 }
 
+/* GetConnectCommand 대신 in-process transport 로 접속 가능한 node
+ * transport 가 nil 이면 GetConnectCommand 사용
+ */
+type TransportInterface interface {
+	GetTransport() (proc.Transport, *errors.Error)
+}
+
+const SSH_TRANSPORT_NATIVE = "native"
+
 type NodeInterface interface {
 	MapTo(section *ini.Section) *errors.Error
 	GetConnectCommand() (string, *errors.Error)
@@ -56,6 +68,15 @@ type NodeSSH struct {
 	Password     string `ini:"password"`
 	CharacterSet string `ini:"character_set"`
 	Eol          string `ini:"eol"`
+
+	/* transport = native 이면 /usr/bin/ssh 대신 in-process ssh client 사용
+	 */
+	Transport     string   `ini:"transport"`
+	KeyFile       []string `ini:"key_file"`
+	KeyPassphrase string   `ini:"key_passphrase"`
+	Agent         bool     `ini:"agent"`
+	HostKey       string   `ini:"host_key"`
+	Keepalive     int      `ini:"keepalive"`
 }
 
 func (self *NodeSSH) MapTo(section *ini.Section) *errors.Error {
@@ -80,12 +101,46 @@ func (self *NodeSSH) GetConnectCommand() (string, *errors.Error) {
 }
 
 func (self *NodeSSH) GetLoginRcmdList(callback func(needExpectFlag bool, sendStr string)) *errors.Error {
+	/* native transport 는 ssh 프로토콜로 인증
+	 */
+	if self.IsNativeTransport() {
+		return nil
+	}
+
 	if len(self.Password) > 0 {
 		callback(true, self.Password)
 	}
 	return nil
 }
 
+func (self *NodeSSH) IsNativeTransport() bool {
+	return strings.ToLower(strings.TrimSpace(self.Transport)) == SSH_TRANSPORT_NATIVE
+}
+
+func (self *NodeSSH) GetTransport() (proc.Transport, *errors.Error) {
+	if !self.IsNativeTransport() {
+		return nil, nil
+	}
+
+	if len(self.Username) == 0 || len(self.Ip) == 0 || self.Port <= 0 {
+		return nil, errors.New("ssh config. has invalid arguments")
+	}
+
+	transport := proc.SSHTransport{
+		Ip:            self.Ip,
+		Port:          self.Port,
+		Username:      self.Username,
+		Password:      self.Password,
+		KeyFiles:      self.KeyFile,
+		KeyPassphrase: self.KeyPassphrase,
+		AgentFlag:     self.Agent,
+		HostKey:       self.HostKey,
+		Keepalive:     self.Keepalive,
+	}
+
+	return &transport, nil
+}
+
 func (self *NodeSSH) GetString(field string) (string, *errors.Error) {
 	v, err := getFieldValue(reflect.ValueOf(self), field)
 	if err != nil {
@@ -129,6 +184,11 @@ func (self *NodeSSH) Dump(depth string) {
 	fmt.Println(depth+" Password:", self.Password)
 	fmt.Println(depth+" CharacterSet:", self.CharacterSet)
 	fmt.Println(depth+" Eol:", self.Eol)
+	fmt.Println(depth+" Transport:", self.Transport)
+	fmt.Println(depth+" KeyFile:", self.KeyFile)
+	fmt.Println(depth+" Agent:", self.Agent)
+	fmt.Println(depth+" HostKey:", self.HostKey)
+	fmt.Println(depth+" Keepalive:", self.Keepalive)
 }
 
 // Telnet
@@ -388,6 +448,203 @@ func (self *NodeCisco) Dump(depth string) {
 	fmt.Println(depth+" Eol:", self.Eol)
 }
 
+// Serial
+type NodeSerial struct {
+	Device       string `ini:"device"`
+	Baud         int    `ini:"baud"`
+	DataBits     int    `ini:"data_bits"`
+	Parity       string `ini:"parity"`       // none, odd, even
+	StopBits     int    `ini:"stop_bits"`    // 1, 2
+	FlowControl  string `ini:"flow_control"` // none, hardware, software
+	Username     string `ini:"username"`
+	Password     string `ini:"password"`
+	CharacterSet string `ini:"character_set"`
+	Eol          string `ini:"eol"`
+}
+
+func (self *NodeSerial) MapTo(section *ini.Section) *errors.Error {
+	self.Baud = 9600
+	self.DataBits = 8
+	self.Parity = "none"
+	self.StopBits = 1
+	self.FlowControl = "none"
+	self.Eol = constdef.EOL_CR
+	self.CharacterSet = constdef.DEFAULT_CHARACTER_SET
+
+	oserr := section.MapTo(self)
+	if oserr != nil {
+		return errors.New(fmt.Sprintf("%s", oserr))
+	}
+
+	return nil
+}
+
+func (self *NodeSerial) GetConnectCommand() (string, *errors.Error) {
+	return "", errors.New("NodeSerial doesn't support connection command")
+}
+
+func (self *NodeSerial) GetTransport() (proc.Transport, *errors.Error) {
+	if len(self.Device) == 0 {
+		return nil, errors.New("serial config. has invalid arguments")
+	}
+
+	transport := proc.SerialTransport{
+		Device:      self.Device,
+		Baud:        self.Baud,
+		DataBits:    self.DataBits,
+		Parity:      self.Parity,
+		StopBits:    self.StopBits,
+		FlowControl: self.FlowControl,
+	}
+
+	return &transport, nil
+}
+
+func (self *NodeSerial) GetLoginRcmdList(callback func(needExpectFlag bool, sendStr string)) *errors.Error {
+	/* console 은 입력이 있어야 prompt 출력
+	 */
+	callback(false, "")
+
+	if len(self.Username) > 0 {
+		callback(true, self.Username)
+
+		if len(self.Password) > 0 {
+			callback(true, self.Password)
+		}
+	}
+	return nil
+}
+
+func (self *NodeSerial) GetString(field string) (string, *errors.Error) {
+	v, err := getFieldValue(reflect.ValueOf(self), field)
+	if err != nil {
+		return "", err
+	}
+
+	return v.String(), nil
+}
+
+func (self *NodeSerial) GetInt(field string) (int, *errors.Error) {
+	v, err := getFieldValue(reflect.ValueOf(self), field)
+	if err != nil {
+		return int(-1), err
+	}
+
+	return int(v.Int()), nil
+}
+
+func (self *NodeSerial) GetArrInt(field string) ([]int, *errors.Error) {
+	v, err := getFieldValue(reflect.ValueOf(self), field)
+	if err != nil {
+		return []int{}, err
+	}
+
+	return v.Interface().([]int), nil
+}
+
+func (self *NodeSerial) CanBash() bool {
+	return false
+}
+
+func (self *NodeSerial) NewEmpty() NodeInterface {
+	return &NodeSerial{}
+}
+
+func (self *NodeSerial) Dump(depth string) {
+	fmt.Println(depth + "NodeSerial:")
+	fmt.Println(depth+" Device:", self.Device)
+	fmt.Println(depth+" Baud:", self.Baud)
+	fmt.Println(depth+" DataBits:", self.DataBits)
+	fmt.Println(depth+" Parity:", self.Parity)
+	fmt.Println(depth+" StopBits:", self.StopBits)
+	fmt.Println(depth+" FlowControl:", self.FlowControl)
+	fmt.Println(depth+" Username:", self.Username)
+	fmt.Println(depth+" Password:", self.Password)
+	fmt.Println(depth+" CharacterSet:", self.CharacterSet)
+	fmt.Println(depth+" Eol:", self.Eol)
+}
+
+// Breaking Point
+type NodeBP struct {
+	Ip            string `ini:"ip"`
+	RestPort      int    `ini:"rest_port"`
+	RestProtocol  string `ini:"rest_protocol"` // http, https
+	RestApiPath   string `ini:"rest_api"`
+	LoginUsername string `ini:"login_username"`
+	LoginPassword string `ini:"login_password"`
+	Slot          int    `ini:"slot"`
+	Ports         []int  `ini:"ports"`
+}
+
+func (self *NodeBP) MapTo(section *ini.Section) *errors.Error {
+	self.RestPort = 443
+	self.RestProtocol = "https"
+	self.RestApiPath = "etc:restapi/bp"
+	self.Slot = 1
+
+	oserr := section.MapTo(self)
+	if oserr != nil {
+		return errors.New(fmt.Sprintf("%s", oserr))
+	}
+
+	return nil
+}
+
+func (self *NodeBP) GetConnectCommand() (string, *errors.Error) {
+	return "", errors.New("NodeBP doesn't support connection command")
+}
+
+func (self *NodeBP) GetLoginRcmdList(callback func(needExpectFlag bool, sendStr string)) *errors.Error {
+	return errors.New("NodeBP doesn't support connection login rcmd list")
+}
+
+func (self *NodeBP) GetString(field string) (string, *errors.Error) {
+	v, err := getFieldValue(reflect.ValueOf(self), field)
+	if err != nil {
+		return "", err
+	}
+
+	return v.String(), nil
+}
+
+func (self *NodeBP) GetInt(field string) (int, *errors.Error) {
+	v, err := getFieldValue(reflect.ValueOf(self), field)
+	if err != nil {
+		return int(-1), err
+	}
+
+	return int(v.Int()), nil
+}
+
+func (self *NodeBP) GetArrInt(field string) ([]int, *errors.Error) {
+	v, err := getFieldValue(reflect.ValueOf(self), field)
+	if err != nil {
+		return []int{}, err
+	}
+
+	return v.Interface().([]int), nil
+}
+
+func (self *NodeBP) CanBash() bool {
+	return false
+}
+
+func (self *NodeBP) NewEmpty() NodeInterface {
+	return &NodeBP{}
+}
+
+func (self *NodeBP) Dump(depth string) {
+	fmt.Println(depth + "NodeBP:")
+	fmt.Println(depth+" Ip:", self.Ip)
+	fmt.Println(depth+" RestPort:", self.RestPort)
+	fmt.Println(depth+" RestProtocol:", self.RestProtocol)
+	fmt.Println(depth+" RestApiPath:", self.RestApiPath)
+	fmt.Println(depth+" LoginUsername:", self.LoginUsername)
+	fmt.Println(depth+" LoginPassword:", self.LoginPassword)
+	fmt.Println(depth+" Slot:", self.Slot)
+	fmt.Println(depth+" Ports:", self.Ports)
+}
+
 // Docker Container
 // 더 구상 필요
 type NodeDockerContainer struct {
@@ -464,12 +721,14 @@ func (self *NodeDockerContainer) Dump(depth string) {
 type Node struct {
 	Name     string
 	NodeType string `ini:"type"`
+	Via      string `ini:"via"` /* jump host node 이름 */
 	NodeInfo NodeInterface
 }
 
 func (self *Node) Dump(depth string) {
 	fmt.Println(depth+"Name:", self.Name)
 	fmt.Println(depth+"NodeType:", self.NodeType)
+	fmt.Println(depth+"Via:", self.Via)
 	self.NodeInfo.Dump(depth)
 }
 
@@ -555,9 +814,57 @@ func (self *Env) load() *errors.Error {
 		self.NodeList[node.Name] = node
 	}
 
+	/* via(jump host) 검사
+	 */
+	for name, node := range self.NodeList {
+		if len(node.Via) == 0 {
+			continue
+		}
+
+		if transportNode, ok := node.NodeInfo.(TransportInterface); ok {
+			transport, err := transportNode.GetTransport()
+			if err != nil {
+				return err
+			}
+			if transport != nil {
+				return errors.New(fmt.Sprintf("'%s' node has via, in-process transport can't be used", name))
+			}
+		}
+
+		_, err := self.GetNodeChain(name)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+/* via 로 연결된 jump host 부터 nodename 까지 접속 순서대로 return
+ */
+func (self *Env) GetNodeChain(nodename string) ([]*Node, *errors.Error) {
+	chain := []*Node{}
+	visited := make(map[string]bool)
+
+	name := nodename
+	for len(name) > 0 {
+		if visited[name] {
+			return nil, errors.New(fmt.Sprintf("'%s' node has via cycle", nodename))
+		}
+		visited[name] = true
+
+		node := self.GetNode(name)
+		if node == nil {
+			return nil, errors.New(fmt.Sprintf("'%s' via node is not defined", name))
+		}
+
+		chain = append([]*Node{node}, chain...)
+		name = node.Via
+	}
+
+	return chain, nil
+}
+
 func (self *Env) GetNode(nodename string) *Node {
 	node, ok := self.NodeList[nodename]
 	if ok {
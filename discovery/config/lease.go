@@ -0,0 +1,440 @@
+package config
+
+import (
+	"discovery/constdef"
+	"discovery/errors"
+	"discovery/fmt"
+	"discovery/utils"
+	"encoding/json"
+	"io"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+/* replayer process 간 env 사용 lease
+ * CONTENTS_ROOT/lease/<envid>.lease 파일에 holder 정보 기록
+ * 파일 read, write 는 fcntl lock 으로 보호
+ */
+type Lease struct {
+	EnvId     string
+	Username  string
+	Hostname  string
+	Pid       int
+	Rid       string
+	Timestamp string // lease 획득 시간
+	Heartbeat string // 마지막 heartbeat 시간
+
+	refCount int
+	stopChan chan bool
+}
+
+var LEASE_TIME_FORMAT string = "2006-01-02 15:04:05"
+
+/* 같은 process 에서 획득한 lease, fcntl lock 은 process 단위라 process 내 record 간에는 leaseMap 으로 배타 처리
+ * leaseMap, lease 파일 갱신은 mutex 로 보호
+ */
+var (
+	leaseMutex sync.Mutex
+	leaseMap   = make(map[string]*Lease)
+)
+
+/* env lease 획득
+ * lockwait 0: 대기 안함, 0 보다 크면 lockwait 초 동안 대기, 0 보다 작으면 계속 대기
+ * parent 가 같은 env 의 lease 이면 재사용, require record 는 상위 record 의 lease 를 parent 로 사용
+ */
+func AcquireLease(envid string, rid string, lockwait int, parent *Lease) (*Lease, *errors.Error) {
+	if len(envid) == 0 {
+		return nil, errors.New("Invalid arguments")
+	}
+
+	if parent.reuse(envid) {
+		return parent, nil
+	}
+
+	hostname, _ := os.Hostname()
+	username := os.Getenv("USER")
+	if current, goerr := user.Current(); goerr == nil {
+		username = current.Username
+	}
+
+	now := time.Now().Format(LEASE_TIME_FORMAT)
+	lease := Lease{
+		EnvId:     envid,
+		Username:  username,
+		Hostname:  hostname,
+		Pid:       os.Getpid(),
+		Rid:       rid,
+		Timestamp: now,
+		Heartbeat: now,
+		refCount:  1,
+	}
+
+	/* 대기 중에는 mutex 를 잡지 않아 다른 lease 의 heartbeat, release 가 멈추지 않게 함
+	 */
+	start := time.Now()
+	waitflag := false
+	for {
+		holder, err := lease.acquireOnce()
+		if err != nil {
+			return nil, err
+		}
+
+		if holder == nil {
+			break
+		}
+
+		if lockwait == 0 || (lockwait > 0 && time.Since(start) >= time.Second*time.Duration(lockwait)) {
+			return nil, errors.New(fmt.Sprintf("'%s' env is leased by %s", envid, holder.ToString()))
+		}
+
+		if !waitflag {
+			fmt.Printf("WAIT: '%s' env is leased by %s\n", envid, holder.ToString())
+			waitflag = true
+		}
+		time.Sleep(time.Second)
+	}
+
+	go lease.heartbeat()
+
+	return &lease, nil
+}
+
+/* parent 가 envid 의 lease 를 보유중이면 reference count 증가
+ * acquireOnce 도 leaseMutex 를 잡으므로 return 전에 unlock
+ */
+func (self *Lease) reuse(envid string) bool {
+	if self == nil || self.EnvId != envid {
+		return false
+	}
+
+	leaseMutex.Lock()
+	defer leaseMutex.Unlock()
+
+	if leaseMap[envid] != self {
+		return false
+	}
+
+	self.refCount += 1
+	return true
+}
+
+/* lease 획득 한번 시도, 같은 process 또는 다른 process 의 holder 가 있으면 holder 반환
+ */
+func (self *Lease) acquireOnce() (*Lease, *errors.Error) {
+	leaseMutex.Lock()
+	defer leaseMutex.Unlock()
+
+	if holder, ok := leaseMap[self.EnvId]; ok {
+		return holder, nil
+	}
+
+	holder, err := self.tryAcquire()
+	if err != nil || holder != nil {
+		return holder, err
+	}
+
+	self.stopChan = make(chan bool)
+	leaseMap[self.EnvId] = self
+	return nil, nil
+}
+
+/* lease 파일이 비어 있거나 stale 이면 lease 기록
+ * 다른 holder 가 사용중이면 holder 반환
+ */
+func (self *Lease) tryAcquire() (*Lease, *errors.Error) {
+	fp, err := openLeaseFile(self.EnvId)
+	if err != nil {
+		return nil, err
+	}
+	defer fp.Close()
+
+	err = utils.SetFileLock(fp)
+	if err != nil {
+		return nil, err
+	}
+	defer utils.SetFileUnlock(fp)
+
+	holder, err := readLease(fp)
+	if err != nil {
+		return nil, err
+	}
+
+	if holder != nil {
+		if !holder.IsStale() {
+			return holder, nil
+		}
+		fmt.Printf("WARN: '%s' env stale lease is released. %s\n", self.EnvId, holder.ToString())
+	}
+
+	err = writeLease(fp, self)
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+/* lease 반환, 같은 process 에서 모두 반환해야 lease 파일 비움
+ */
+func (self *Lease) Release() *errors.Error {
+	leaseMutex.Lock()
+	defer leaseMutex.Unlock()
+
+	self.refCount -= 1
+	if self.refCount > 0 {
+		return nil
+	}
+
+	delete(leaseMap, self.EnvId)
+	close(self.stopChan)
+
+	return self.update(func(fp *os.File) *errors.Error {
+		return writeLease(fp, nil)
+	})
+}
+
+/* lease 파일의 holder 가 자신인 경우 fn 실행
+ * 강제 release 되어 다른 holder 가 있으면 아무것도 안함
+ */
+func (self *Lease) update(fn func(fp *os.File) *errors.Error) *errors.Error {
+	fp, err := openLeaseFile(self.EnvId)
+	if err != nil {
+		return err
+	}
+	defer fp.Close()
+
+	err = utils.SetFileLock(fp)
+	if err != nil {
+		return err
+	}
+	defer utils.SetFileUnlock(fp)
+
+	holder, err := readLease(fp)
+	if err != nil {
+		return err
+	}
+
+	if holder == nil || !holder.IsSame(self) {
+		return nil
+	}
+
+	return fn(fp)
+}
+
+func (self *Lease) heartbeat() {
+	ticker := time.NewTicker(time.Second * constdef.LEASE_HEARTBEAT_INTERVAL)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			leaseMutex.Lock()
+			self.Heartbeat = time.Now().Format(LEASE_TIME_FORMAT)
+			self.update(func(fp *os.File) *errors.Error {
+				return writeLease(fp, self)
+			})
+			leaseMutex.Unlock()
+		case <-self.stopChan:
+			return
+		}
+	}
+}
+
+func (self *Lease) IsSame(lease *Lease) bool {
+	return self.Hostname == lease.Hostname && self.Pid == lease.Pid && self.Timestamp == lease.Timestamp
+}
+
+/* heartbeat 가 갱신되지 않거나, 같은 host 에서 holder process 가 없으면 stale
+ */
+func (self *Lease) IsStale() bool {
+	heartbeat, goerr := time.ParseInLocation(LEASE_TIME_FORMAT, self.Heartbeat, time.Local)
+	if goerr != nil || time.Since(heartbeat) > time.Second*constdef.LEASE_STALE_TIMEOUT {
+		return true
+	}
+
+	hostname, _ := os.Hostname()
+	if self.Hostname == hostname {
+		if goerr := syscall.Kill(self.Pid, 0); goerr == syscall.ESRCH {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (self *Lease) ToString() string {
+	return fmt.Sprintf("%s@%s pid %d, rid %s, since %s", self.Username, self.Hostname, self.Pid, self.Rid, self.Timestamp)
+}
+
+/* lease 목록, 비어 있는 lease 파일은 제외
+ */
+func GetLeaseList() ([]*Lease, *errors.Error) {
+	dir, err := GetContentsLeaseDir()
+	if err != nil {
+		return nil, err
+	}
+
+	leaseList := []*Lease{}
+	if !utils.IsExist(dir) {
+		return leaseList, nil
+	}
+
+	goerr := filepath.Walk(dir, func(path string, info os.FileInfo, goerr error) error {
+		if goerr != nil {
+			return goerr
+		}
+		if info.IsDir() || !strings.HasSuffix(path, ".lease") {
+			return nil
+		}
+
+		data, goerr := os.ReadFile(path)
+		if goerr != nil {
+			return goerr
+		}
+
+		if len(strings.TrimSpace(string(data))) == 0 {
+			return nil
+		}
+
+		lease := Lease{}
+		if goerr := json.Unmarshal(data, &lease); goerr != nil {
+			return fmt.Errorf("%s, %s", path, goerr)
+		}
+		leaseList = append(leaseList, &lease)
+		return nil
+	})
+	if goerr != nil {
+		return nil, errors.New(fmt.Sprintf("%s", goerr))
+	}
+
+	sort.Slice(leaseList, func(i, j int) bool {
+		return leaseList[i].EnvId < leaseList[j].EnvId
+	})
+
+	return leaseList, nil
+}
+
+/* holder 와 상관없이 lease 파일 비움
+ */
+func ForceReleaseLease(envid string) (*Lease, *errors.Error) {
+	path, err := GetContentsLeaseFilePath(envid)
+	if err != nil {
+		return nil, err
+	}
+
+	if !utils.IsExist(path) {
+		return nil, errors.New(fmt.Sprintf("'%s' env lease is not exist", envid))
+	}
+
+	fp, err := openLeaseFile(envid)
+	if err != nil {
+		return nil, err
+	}
+	defer fp.Close()
+
+	err = utils.SetFileLock(fp)
+	if err != nil {
+		return nil, err
+	}
+	defer utils.SetFileUnlock(fp)
+
+	holder, err := readLease(fp)
+	if err != nil {
+		return nil, err
+	}
+
+	if holder == nil {
+		return nil, errors.New(fmt.Sprintf("'%s' env is not leased", envid))
+	}
+
+	err = writeLease(fp, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return holder, nil
+}
+
+/* 여러 사용자가 공유하므로 디렉토리, 파일 모두 쓰기 가능하도록 생성
+ */
+func openLeaseFile(envid string) (*os.File, *errors.Error) {
+	path, err := GetContentsLeaseFilePath(envid)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Dir(path)
+	if !utils.IsExist(dir) {
+		if goerr := os.MkdirAll(dir, 0777); goerr != nil {
+			return nil, errors.New(fmt.Sprintf("%s", goerr))
+		}
+		os.Chmod(dir, 0777)
+	}
+
+	newflag := !utils.IsExist(path)
+
+	fp, goerr := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0666)
+	if goerr != nil {
+		return nil, errors.New(fmt.Sprintf("%s", goerr))
+	}
+
+	if newflag {
+		fp.Chmod(0666)
+	}
+
+	return fp, nil
+}
+
+/* 비어 있으면 nil
+ * 같은 파일을 다시 open, close 하면 fcntl lock 이 풀리므로 lock 을 잡은 fp 로 읽음
+ */
+func readLease(fp *os.File) (*Lease, *errors.Error) {
+	if _, goerr := fp.Seek(0, io.SeekStart); goerr != nil {
+		return nil, errors.New(fmt.Sprintf("%s", goerr))
+	}
+
+	data, goerr := io.ReadAll(fp)
+	if goerr != nil {
+		return nil, errors.New(fmt.Sprintf("%s", goerr))
+	}
+
+	if len(strings.TrimSpace(string(data))) == 0 {
+		return nil, nil
+	}
+
+	lease := Lease{}
+	if goerr := json.Unmarshal(data, &lease); goerr != nil {
+		return nil, errors.New(fmt.Sprintf("%s, %s", fp.Name(), goerr))
+	}
+
+	return &lease, nil
+}
+
+/* lease 가 nil 이면 파일 비움
+ */
+func writeLease(fp *os.File, lease *Lease) *errors.Error {
+	data := []byte{}
+	if lease != nil {
+		var goerr error
+		data, goerr = json.MarshalIndent(lease, "", "  ")
+		if goerr != nil {
+			return errors.New(fmt.Sprintf("%s", goerr))
+		}
+	}
+
+	if goerr := fp.Truncate(0); goerr != nil {
+		return errors.New(fmt.Sprintf("%s", goerr))
+	}
+
+	if _, goerr := fp.WriteAt(data, 0); goerr != nil {
+		return errors.New(fmt.Sprintf("%s", goerr))
+	}
+
+	return nil
+}
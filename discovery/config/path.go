@@ -192,6 +192,22 @@ func GetContentsResultsDir() (string, *errors.Error) {
 	return fmt.Sprintf("%s/results", dir), nil
 }
 
+func GetContentsLeaseDir() (string, *errors.Error) {
+	dir, err := GetContentsRoot()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s/lease", dir), nil
+}
+
+func GetContentsLeaseFilePath(envid string) (string, *errors.Error) {
+	dir, err := GetContentsLeaseDir()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s/%s.lease", dir, envid), nil
+}
+
 func GetContentsSetsDir() (string, *errors.Error) {
 	dir, err := GetContentsRoot()
 	if err != nil {
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"discovery/errors"
+	"discovery/fmt"
+	"strings"
+
+	"github.com/go-ini/ini"
+)
+
+/* etc/report_server.ini, replay 결과를 syslog 로 전송할 서버
+ */
+type ReportServer struct {
+	ConfPath string
+	Ip       string
+	Port     int
+	Protocol string // udp, tcp
+	Facility int    // syslog facility, default 16(local0)
+}
+
+func NewReportServer() (*ReportServer, *errors.Error) {
+	path, err := GetDiscoveryEtcReportserverConfPath()
+	if err != nil {
+		return nil, err
+	}
+
+	conf, goerr := ini.Load(path)
+	if goerr != nil {
+		return nil, errors.New(fmt.Sprintf("%s", goerr))
+	}
+
+	section := conf.Section("SERVER")
+
+	server := ReportServer{
+		ConfPath: path,
+		Ip:       strings.TrimSpace(section.Key("ip").String()),
+		Port:     section.Key("port").MustInt(514),
+		Protocol: strings.ToLower(strings.TrimSpace(section.Key("protocol").MustString("udp"))),
+		Facility: section.Key("facility").MustInt(16),
+	}
+
+	if len(server.Ip) == 0 {
+		return nil, errors.New(fmt.Sprintf("%s, ip is not set", path))
+	}
+
+	switch server.Protocol {
+	case "udp", "tcp":
+	default:
+		return nil, errors.New(fmt.Sprintf("%s, '%s' is invalid protocol. udp, tcp can available", path, server.Protocol))
+	}
+
+	if server.Facility < 0 || server.Facility > 23 {
+		return nil, errors.New(fmt.Sprintf("%s, '%d' is invalid facility. 0 ~ 23 can available", path, server.Facility))
+	}
+
+	return &server, nil
+}
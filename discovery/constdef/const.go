@@ -76,9 +76,8 @@ var TMP_DIR string = "/tmp/discovery"
 
 var DEBUG_MODE_BASH_PROMPT string = "bash-"
 
-var DEFAULT_EXPECT_TIMEOUT float64 = 10       // 10초
-var LOGIN_EXPECT_TIMEOUT float64 = 120        // 120초, rss 패킷 생성 worker의 경우 로드가 많아 느림
-var DEFAULT_EXPECT_TIMEOUT_STEP float64 = 250 // 250ms
+var DEFAULT_EXPECT_TIMEOUT float64 = 10 // 10초
+var LOGIN_EXPECT_TIMEOUT float64 = 120  // 120초, rss 패킷 생성 worker의 경우 로드가 많아 느림
 
 var DEFAULT_CHARACTER_SET string = "utf8"
 
@@ -99,6 +98,15 @@ var MAX_RECORDER_OUTPUT_LINE_COUNT uint32 = 500
 var OUTPUT_STRING_VARIABLE_NAME string = "output_string"
 var EXIT_CODE_VARIABLE_NAME string = "exit_code"
 
+/* multi pattern expect 결과 변수 이름
+ */
+var EXPECT_INDEX_VARIABLE_NAME string = "expect_index"
+var EXPECT_STRING_VARIABLE_NAME string = "expect_string"
+
+/* expect regexp capture group 변수 이름
+ */
+var EXPECT_MATCH_VARIABLE_NAME string = "expect_match"
+
 /* rest response 변수 suffix
  */
 var REST_RESPONSE_IS_SUCCESS_VARIABLE_NAME_SUFFIX string = "_success"
@@ -107,6 +115,31 @@ var REST_RESPONSE_BODY_VARIABLE_NAME_SUFFIX string = "_response"
 
 var ARGS_VARIABLE_NAME string = "args"
 
+/* env lease heartbeat 주기, heartbeat 가 stale timeout 동안 갱신 안되면 stale lease
+ */
+var LEASE_HEARTBEAT_INTERVAL time.Duration = 10 // 10초
+var LEASE_STALE_TIMEOUT time.Duration = 60      // 60초
+
+/* pty output stream buffer 최대 크기, 넘으면 consume 될때까지 output 읽기 대기
+ */
+var OUTPUT_STREAM_MAX_SIZE int = 1024 * 1024
+
+/* monitor 로 수집하는 최대 line 수, 넘으면 오래된 line 부터 삭제
+ */
+var MONITOR_MAX_LINE_COUNT int = 10000
+
+/* monitor 수집 시간 변수 suffix
+ */
+var MONITOR_TIME_VARIABLE_NAME_SUFFIX string = "_time"
+
+/* time 함수 기본 layout, parse 시 소수점 이하 초는 layout 에 없어도 허용
+ */
+var TIME_FUNCTION_LAYOUT string = "2006-01-02 15:04:05"
+
+/* proc 재귀 호출 최대 depth
+ */
+var MAX_PROC_CALL_DEPTH int = 100
+
 /* debug flag
  */
 var DEBUG bool = true
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"discovery/config"
+	"discovery/constdef"
+	"discovery/errors"
+	"discovery/fmt"
+	"flag"
+	"os"
+)
+
+type arg struct {
+	ReleaseEnvId string
+}
+
+func parseArg() (*arg, *errors.Error) {
+	releasePtr := flag.String("release", "", "env id to force release")
+
+	flag.Parse()
+
+	arg1 := arg{
+		ReleaseEnvId: *releasePtr,
+	}
+
+	return &arg1, nil
+}
+
+func help() {
+	fmt.Println("Envlock")
+	fmt.Println("  without arguments, list env leases")
+	fmt.Println("  -release env id, force release env lease")
+	fmt.Println(`ex) envlock`)
+	fmt.Println(`ex) envlock -release "network/single_route"`)
+}
+
+func main() {
+	arg, err := parseArg()
+	if err != nil {
+		help()
+		fmt.Fprintln(os.Stderr, "ERR:", err.ToString(constdef.DEBUG))
+		os.Exit(1)
+	}
+
+	config.Version()
+
+	if len(arg.ReleaseEnvId) > 0 {
+		holder, err := config.ForceReleaseLease(arg.ReleaseEnvId)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "ERR:", err.ToString(constdef.DEBUG))
+			os.Exit(1)
+		}
+
+		fmt.Printf("'%s' env lease is released. %s\n", arg.ReleaseEnvId, holder.ToString())
+		return
+	}
+
+	leaseList, err := config.GetLeaseList()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "ERR:", err.ToString(constdef.DEBUG))
+		os.Exit(1)
+	}
+
+	if len(leaseList) == 0 {
+		fmt.Println("There are no env leases")
+		return
+	}
+
+	fmt.Printf("%-30s %-6s %-20s %-8s %-30s %-19s %-19s\n", "ENV", "STATUS", "HOLDER", "PID", "RID", "SINCE", "HEARTBEAT")
+	for _, lease := range leaseList {
+		status := "active"
+		if lease.IsStale() {
+			status = "stale"
+		}
+
+		fmt.Printf("%-30s %-6s %-20s %-8d %-30s %-19s %-19s\n", lease.EnvId, status,
+			fmt.Sprintf("%s@%s", lease.Username, lease.Hostname), lease.Pid, lease.Rid, lease.Timestamp, lease.Heartbeat)
+	}
+}
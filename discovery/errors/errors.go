@@ -13,7 +13,8 @@ type Error struct {
 	Func      string
 	Line      int
 	Msg       string
-	CheckCode uint8 `json:"-"` /* record2 호환 */
+	Position  string `json:",omitempty"` // error 가 발생한 record 위치, file:line:column
+	CheckCode uint8  `json:"-"`          /* record2 호환 */
 }
 
 const (
@@ -42,10 +43,15 @@ func New(msg string) *Error {
 }
 
 func (self *Error) ToString(debugFlag bool) string {
+	msg := self.Msg
+	if len(self.Position) > 0 {
+		msg = fmt.Sprintf("%s: %s", self.Position, self.Msg)
+	}
+
 	if debugFlag {
-		return fmt.Sprintf("(%s:%d) %s(), %s", self.File, self.Line, self.Func, self.Msg)
+		return fmt.Sprintf("(%s:%d) %s(), %s", self.File, self.Line, self.Func, msg)
 	} else {
-		return fmt.Sprintf("%s", self.Msg)
+		return fmt.Sprintf("%s", msg)
 	}
 }
 
@@ -62,3 +68,12 @@ func (self *Error) AppendMsg(msg string) *Error {
 	}
 	return self
 }
+
+/* record 위치는 처음 설정된 값 유지, nested rcmd 에서 가장 안쪽 위치
+ */
+func (self *Error) SetPosition(position string) *Error {
+	if len(self.Position) == 0 && len(position) > 0 {
+		self.Position = position
+	}
+	return self
+}
@@ -1,11 +1,13 @@
 package fmt
 
 import (
+	"bytes"
 	"encoding/base64"
 	"errors"
 	"fmt"
 	"io"
 	"os"
+	"sync"
 )
 
 var MultiWriterFp io.Writer = nil
@@ -15,6 +17,10 @@ var (
 	webFlag = false
 )
 
+/* MultiWriterFp 출력 lock, 병렬 실행시 출력이 섞이지 않게 함
+ */
+var mutex sync.Mutex
+
 func InitPrintWeb(paths []string) error {
 	webFlag = true
 	fplist := []io.Writer{}
@@ -51,41 +57,90 @@ func InitPrint(paths []string) error {
 }
 
 func Printf(format string, a ...interface{}) (n int, err error) {
-	if MultiWriterFp == nil {
-		return fmt.Printf(format, a...)
-	} else {
-		msg := fmt.Sprintf(format, a...)
-		n, err = MultiWriterFp.Write([]byte(msg))
-		if err == nil && webFlag == true {
-			encoded_msg := base64.StdEncoding.EncodeToString([]byte(msg))
-			std_msg := fmt.Sprintf("{\"offset\": \"%d\", \"msg\": \"%s\", \"type\": \"printf\"}", offset, encoded_msg)
-			_, std_err := fmt.Println(std_msg)
-			if std_err != nil {
-				return n, std_err
-			}
-			offset += n
-		}
-		return n, err
-	}
+	return write(fmt.Sprintf(format, a...), "printf")
 }
 
 func Println(a ...interface{}) (n int, err error) {
+	return write(fmt.Sprintln(a...), "println")
+}
+
+func write(msg string, msgType string) (n int, err error) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	return output(msg, msgType)
+}
+
+/* mutex lock 후 호출
+ */
+func output(msg string, msgType string) (n int, err error) {
 	if MultiWriterFp == nil {
-		return fmt.Println(a...)
-	} else {
-		msg := fmt.Sprintln(a...)
-		n, err = MultiWriterFp.Write([]byte(msg))
-		if err == nil && webFlag == true {
-			encoded_msg := base64.StdEncoding.EncodeToString([]byte(msg))
-			std_msg := fmt.Sprintf("{\"offset\": \"%d\", \"msg\": \"%s\", \"type\": \"println\"}", offset, encoded_msg)
-			_, std_err := fmt.Println(std_msg)
-			if std_err != nil {
-				return n, std_err
-			}
-			offset += n
+		return fmt.Print(msg)
+	}
+
+	n, err = MultiWriterFp.Write([]byte(msg))
+	if err == nil && webFlag == true {
+		encoded_msg := base64.StdEncoding.EncodeToString([]byte(msg))
+		std_msg := fmt.Sprintf("{\"offset\": \"%d\", \"msg\": \"%s\", \"type\": \"%s\"}", offset, encoded_msg, msgType)
+		_, std_err := fmt.Println(std_msg)
+		if std_err != nil {
+			return n, std_err
 		}
-		return n, err
+		offset += n
 	}
+	return n, err
+}
+
+/* 출력 buffer, parallel 실행시 record, branch 별 출력을 모아두었다가 Flush 할때 parent 로 출력
+ * parent 가 nil 이면 Flush 할때 화면 출력, nil Printer 는 바로 화면 출력
+ */
+type Printer struct {
+	parent *Printer
+	buffer bytes.Buffer
+	mutex  sync.Mutex
+}
+
+func NewBufferPrinter(parent *Printer) *Printer {
+	return &Printer{parent: parent}
+}
+
+func (self *Printer) Printf(format string, a ...interface{}) (n int, err error) {
+	return self.write(fmt.Sprintf(format, a...), "printf")
+}
+
+func (self *Printer) Println(a ...interface{}) (n int, err error) {
+	return self.write(fmt.Sprintln(a...), "println")
+}
+
+func (self *Printer) write(msg string, msgType string) (n int, err error) {
+	if self == nil {
+		return write(msg, msgType)
+	}
+
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	return self.buffer.WriteString(msg)
+}
+
+/* 모아둔 출력을 parent 로 한번에 출력
+ */
+func (self *Printer) Flush() error {
+	if self == nil {
+		return nil
+	}
+
+	self.mutex.Lock()
+	msg := self.buffer.String()
+	self.buffer.Reset()
+	self.mutex.Unlock()
+
+	if len(msg) == 0 {
+		return nil
+	}
+
+	_, err := self.parent.write(msg, "printf")
+	return err
 }
 
 func Sprintf(format string, a ...interface{}) string {
@@ -103,3 +158,7 @@ func Fprintf(w io.Writer, format string, a ...interface{}) (n int, err error) {
 func Fprintln(w io.Writer, a ...interface{}) (n int, err error) {
 	return fmt.Fprintln(w, a...)
 }
+
+func Errorf(format string, a ...interface{}) error {
+	return fmt.Errorf(format, a...)
+}
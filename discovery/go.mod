@@ -12,8 +12,10 @@ require (
 	github.com/lunixbochs/vtclean v1.0.0
 	github.com/tidwall/gjson v1.14.0
 	github.com/totherme/unstructured v0.0.0-20170821094912-3faf2d56d8b8
+	golang.org/x/crypto v0.0.0-20220525230936-793ad666bf5e
 	golang.org/x/text v0.3.7
 	gopkg.in/resty.v1 v1.12.0
+	gopkg.in/yaml.v2 v2.4.0
 )
 
 require (
@@ -26,5 +28,5 @@ require (
 	github.com/tidwall/pretty v1.2.0 // indirect
 	github.com/xeipuuv/gojsonpointer v0.0.0-20190905194746-02993c407bfb // indirect
 	golang.org/x/net v0.0.0-20220225172249-27dd8689420f // indirect
-	gopkg.in/yaml.v2 v2.4.0 // indirect
+	golang.org/x/sys v0.7.0 // indirect
 )
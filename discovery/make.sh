@@ -7,3 +7,7 @@ go build -o ../bin/recorder recorder.go
 go build -o ../bin/replayer replayer.go
 go build -o ../bin/spec spec.go
 go build -o ../bin/envhash envhash.go
+go build -o ../bin/bpsim bpsim.go
+go build -o ../bin/envlock envlock.go
+go build -o ../bin/report report.go
+go build -o ../bin/resultdiff resultdiff.go
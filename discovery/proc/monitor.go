@@ -0,0 +1,120 @@
+package proc
+
+import (
+	"discovery/errors"
+	"sync"
+	"time"
+)
+
+/* monitor 로 수집한 output line
+ */
+type MonitorLine struct {
+	Time time.Time
+	Line string
+}
+
+/* background 에서 stream 의 완성된 line 을 계속 수집
+ * MaxLineCount 를 넘으면 오래된 line 부터 삭제
+ */
+type Monitor struct {
+	MaxLineCount int
+	DroppedCount int
+
+	mutex    sync.Mutex
+	lines    []*MonitorLine
+	stopChan chan bool
+	doneChan chan bool
+}
+
+/* monitor 중에는 Expect, ExpectScreen 사용 불가
+ */
+func (self *PtyProcess) StartMonitor(maxLineCount int) (*Monitor, *errors.Error) {
+	if self.Monitor != nil {
+		return nil, errors.New("monitor is already started")
+	}
+
+	if maxLineCount <= 0 {
+		return nil, errors.New("invalid max line count")
+	}
+
+	monitor := Monitor{
+		MaxLineCount: maxLineCount,
+		lines:        []*MonitorLine{},
+		stopChan:     make(chan bool),
+		doneChan:     make(chan bool),
+	}
+	self.Monitor = &monitor
+
+	go func() {
+		defer close(monitor.doneChan)
+
+		for {
+			data, closed := self.Stream.Bytes()
+
+			lines, _, lineEnds := splitFullStreamLines(data, self.Stream.Full())
+			if len(lineEnds) > 0 {
+				self.Stream.Consume(lineEnds[len(lineEnds)-1])
+				monitor.add(lines)
+			}
+
+			if closed {
+				return
+			}
+
+			select {
+			case <-monitor.stopChan:
+				return
+			case <-self.Stream.Notify():
+			}
+		}
+	}()
+
+	return &monitor, nil
+}
+
+/* monitor 종료, 수집한 line return
+ * 완성되지 않은 line 은 stream 에 남김
+ */
+func (self *PtyProcess) StopMonitor() ([]*MonitorLine, *errors.Error) {
+	monitor := self.Monitor
+	if monitor == nil {
+		return nil, errors.New("monitor is not started")
+	}
+
+	close(monitor.stopChan)
+	<-monitor.doneChan
+	self.Monitor = nil
+
+	/* stop 이전에 받은 line 추가
+	 */
+	data, _ := self.Stream.Bytes()
+	lines, _, lineEnds := splitStreamLines(data)
+	if len(lineEnds) > 0 {
+		self.Stream.Consume(lineEnds[len(lineEnds)-1])
+		monitor.add(lines)
+	}
+
+	return monitor.Lines(), nil
+}
+
+func (self *Monitor) add(lines []string) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	now := time.Now()
+	for _, line := range lines {
+		self.lines = append(self.lines, &MonitorLine{Time: now, Line: line})
+	}
+
+	if over := len(self.lines) - self.MaxLineCount; over > 0 {
+		self.lines = append([]*MonitorLine{}, self.lines[over:]...)
+		self.DroppedCount += over
+	}
+}
+
+func (self *Monitor) Lines() []*MonitorLine {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	return append([]*MonitorLine{}, self.lines...)
+}
@@ -1,7 +1,6 @@
 package proc
 
 import (
-	"bufio"
 	"discovery/constdef"
 	"discovery/errors"
 	"discovery/fmt"
@@ -9,6 +8,7 @@ import (
 	"github.com/lunixbochs/vtclean"
 	"golang.org/x/text/encoding/korean"
 	"golang.org/x/text/transform"
+	"io"
 	"os"
 	"os/exec"
 	"regexp"
@@ -28,6 +28,7 @@ const (
 	LINE_TYPE_PROMPT
 	LINE_TYPE_SSHAUTH
 	LINE_TYPE_MORE
+	LINE_TYPE_TIMEOUT
 
 	MATCH_TYPE_RE
 	MATCH_TYPE_STR_CONTAIN
@@ -39,16 +40,29 @@ type LineMatch struct {
 	MatchType uint8
 	Re        *regexp.Regexp // MATCH_TYPE_RE
 	Str       string         // MATCH_TYPE_STR_COTAIN, MATCH_TYPE_STR_EXACT
+	MultiLine bool           // 미완성 line 뿐 아니라 받은 전체 line 과 비교
+}
+
+/* PtyProcess 입출력 transport
+ * nil 이면 local pty 에서 command 실행, ssh 처럼 in-process 로 접속하는 경우 사용
+ */
+type Transport interface {
+	Open(rows, cols int) (io.ReadWriter, *errors.Error)
+	Close()
 }
 
 type PtyProcess struct {
 	CommandStr    string
 	ExecCmd       *exec.Cmd
 	Fp            *os.File
-	Reader        *bufio.Reader
+	Transport     Transport
+	Conn          io.ReadWriter
+	Stream        *OutputStream
+	Screen        *Screen
 	OutputChannel chan string
 	CharacterSet  string
 	Eol           string
+	Monitor       *Monitor // monitor 중이면 nil 이 아님
 }
 
 func NewPtyProcess(command string, characterSet string, eol string) (*PtyProcess, *errors.Error) {
@@ -57,6 +71,64 @@ func NewPtyProcess(command string, characterSet string, eol string) (*PtyProcess
 		return nil, errors.New("Invalid command")
 	}
 
+	charSet, eol, err := convCharacterSetEol(characterSet, eol)
+	if err != nil {
+		return nil, err
+	}
+
+	ptyprocess := PtyProcess{
+		CommandStr:    command,
+		ExecCmd:       exec.Command(varArgs[0], varArgs[1:]...),
+		Fp:            nil,
+		Stream:        NewOutputStream(),
+		OutputChannel: make(chan string),
+		CharacterSet:  charSet,
+		Eol:           eol,
+	}
+
+	return &ptyprocess, nil
+}
+
+/* local command 대신 transport 로 입출력하는 process
+ */
+func NewTransportProcess(name string, transport Transport, characterSet string, eol string) (*PtyProcess, *errors.Error) {
+	if transport == nil {
+		return nil, errors.New("Invalid transport")
+	}
+
+	charSet, eol, err := convCharacterSetEol(characterSet, eol)
+	if err != nil {
+		return nil, err
+	}
+
+	ptyprocess := PtyProcess{
+		CommandStr:    name,
+		ExecCmd:       nil,
+		Fp:            nil,
+		Transport:     transport,
+		Stream:        NewOutputStream(),
+		OutputChannel: make(chan string),
+		CharacterSet:  charSet,
+		Eol:           eol,
+	}
+
+	return &ptyprocess, nil
+}
+
+/* jump host 통해 다른 node 로 접속한 경우 접속한 node 의 설정으로 변경
+ */
+func (self *PtyProcess) SetCharacterSetEol(characterSet string, eol string) *errors.Error {
+	charSet, eol, err := convCharacterSetEol(characterSet, eol)
+	if err != nil {
+		return err
+	}
+
+	self.CharacterSet = charSet
+	self.Eol = eol
+	return nil
+}
+
+func convCharacterSetEol(characterSet string, eol string) (string, string, *errors.Error) {
 	charSet := constdef.DEFAULT_CHARACTER_SET
 	switch strings.ToLower(strings.TrimSpace(characterSet)) {
 	case "euckr":
@@ -64,7 +136,7 @@ func NewPtyProcess(command string, characterSet string, eol string) (*PtyProcess
 	case constdef.DEFAULT_CHARACTER_SET:
 	case "":
 	default:
-		return nil, errors.New("Invalid characterSet. euckr, utf8 can available")
+		return "", "", errors.New("Invalid characterSet. euckr, utf8 can available")
 	}
 
 	switch eol {
@@ -75,19 +147,10 @@ func NewPtyProcess(command string, characterSet string, eol string) (*PtyProcess
 	case constdef.EOL_CRLF:
 		eol = "\r\n"
 	default:
-		return nil, errors.New("invalid eol argument")
+		return "", "", errors.New("invalid eol argument")
 	}
 
-	ptyprocess := PtyProcess{
-		CommandStr:    command,
-		ExecCmd:       exec.Command(varArgs[0], varArgs[1:]...),
-		Fp:            nil,
-		OutputChannel: make(chan string),
-		CharacterSet:  charSet,
-		Eol:           eol,
-	}
-
-	return &ptyprocess, nil
+	return charSet, eol, nil
 }
 
 func (self *PtyProcess) Start() *errors.Error {
@@ -96,74 +159,299 @@ func (self *PtyProcess) Start() *errors.Error {
 		return errors.New(fmt.Sprintf("%s", goerr))
 	}
 
-	fp, goerr := pty.StartWithSize(self.ExecCmd, wz)
-	if goerr != nil {
-		return errors.New(fmt.Sprintf("%s", goerr))
+	if self.Transport != nil {
+		conn, err := self.Transport.Open(int(wz.Rows), int(wz.Cols))
+		if err != nil {
+			return err
+		}
+		self.Conn = conn
+	} else {
+		fp, goerr := pty.StartWithSize(self.ExecCmd, wz)
+		if goerr != nil {
+			return errors.New(fmt.Sprintf("%s", goerr))
+		}
+		self.Fp = fp
+		self.Conn = fp
 	}
-	self.Fp = fp
-	self.Reader = bufio.NewReader(fp)
+	self.Screen = NewScreen(int(wz.Rows), int(wz.Cols))
 
 	go func() {
-		defer close(self.OutputChannel)
+		defer self.Stream.Close()
 
+		buf := make([]byte, 4096)
 		for {
-			line, _, err := self.Reader.ReadLine()
+			n, err := self.Conn.Read(buf)
+			if n > 0 {
+				self.Stream.Write(buf[:n])
+				self.Screen.Write(buf[:n])
+			}
 			if err != nil {
 				return
 			}
-			self.OutputChannel <- string(line)
 		}
 	}()
 
 	return nil
 }
 
-func (self *PtyProcess) Read(matchtable []*LineMatch, timeout time.Duration) (string, uint8, *errors.Error) {
-	timeoutCount := timeout
+/* stream 으로 받는 data 를 OutputChannel 로 전달
+ * recorder terminal 처럼 raw output 이 필요한 경우 사용, 이후 Expect 사용 불가
+ */
+func (self *PtyProcess) EnableOutputChannel() chan string {
+	go func() {
+		defer close(self.OutputChannel)
+
+		for {
+			data, ok := self.Stream.Next()
+			if !ok {
+				return
+			}
+			self.OutputChannel <- string(data)
+		}
+	}()
+
+	return self.OutputChannel
+}
+
+/* stream 매칭 결과
+ */
+type StreamMatch struct {
+	LineType uint8
+	Index    int      // 일치된 matchtable index, 없으면 -1
+	Lines    []string // 일치된 위치 이전 output lines
+	Match    string   // 일치된 string
+}
+
+/* matchtable 과 일치하는 data 가 수신될때까지 대기, 일치된 위치까지 stream 에서 삭제
+ * data 가 수신될때마다 새로 받은 data 만 line 으로 분리하여 바로 비교하고,
+ * timeout(ms) 동안 data 수신이 없으면 LINE_TYPE_TIMEOUT
+ * MultiLine matchtable 이 없으면 완성된 line 은 바로 stream 에서 삭제
+ */
+func (self *PtyProcess) Expect(matchtable []*LineMatch, timeout time.Duration) (*StreamMatch, *errors.Error) {
+	if self.Monitor != nil {
+		return nil, errors.New("output is captured by monitor")
+	}
+
+	multiLine := false
+	for _, match := range matchtable {
+		if match.MultiLine {
+			multiLine = true
+		}
+	}
+
+	lines := []string{}
+	lineEnds := []int{} // multi line 인 경우 각 line 다음 line 의 stream offset
+	parsed := 0         // 완성된 line 으로 분리한 stream offset
 
 	for {
+		data, closed := self.Stream.Bytes()
+
+		newLines, _, newEnds := splitFullStreamLines(data[parsed:], self.Stream.Full())
+		for i, line := range newLines {
+			lines = append(lines, line)
+			lineEnds = append(lineEnds, parsed+newEnds[i])
+		}
+		if len(newEnds) > 0 {
+			parsed += newEnds[len(newEnds)-1]
+		}
+
+		if !multiLine && parsed > 0 {
+			self.Stream.Consume(parsed)
+			data = data[parsed:]
+			lineEnds = []int{}
+			parsed = 0
+		}
+
+		tail := vtclean.Clean(string(data[parsed:]), false)
+		result, consumed, err := matchStream(lines, lineEnds, tail, len(data), matchtable)
+		if err != nil {
+			return nil, err
+		}
+
+		if result != nil {
+			self.Stream.Consume(consumed)
+			return result, nil
+		}
+
+		if closed {
+			return nil, errors.New("OutputStream has closed.")
+		}
+
+		var timer <-chan time.Time = nil
+		if timeout > 0 {
+			timer = time.After(time.Millisecond * timeout)
+		}
+
 		select {
-		case line, ok := <-self.OutputChannel:
-			if !ok {
-				return "", 0, errors.New("OutputChannel has closed.")
+		case <-self.Stream.Notify():
+		case <-timer:
+			self.Stream.Consume(parsed)
+
+			return &StreamMatch{
+				LineType: LINE_TYPE_TIMEOUT,
+				Index:    -1,
+				Lines:    lines,
+				Match:    "",
+			}, nil
+		}
+	}
+}
+
+/* matchtable 순서대로 미완성 line(tail) 과 비교, MultiLine 이면 완성된 line 까지 포함하여 비교
+ * 일치하면 매칭 결과와 stream 에서 삭제할 크기 return
+ * XXX: 완성된 line 중간에서 일치하면 해당 line 끝까지 삭제
+ */
+func matchStream(lines []string, lineEnds []int, tail string, size int, matchtable []*LineMatch) (*StreamMatch, int, *errors.Error) {
+	text := ""
+	if len(lineEnds) > 0 {
+		text = strings.Join(append(append([]string{}, lines...), tail), "\n")
+	}
+
+	for index, match := range matchtable {
+		matched := false
+		loc := []int{}
+
+		switch match.MatchType {
+		case MATCH_TYPE_RE:
+			if match.Re == nil {
+				return nil, 0, errors.New("regexp is nil")
 			}
-			return vtclean.Clean(line, false), LINE_TYPE_OUTPUT_LINE, nil
-		case <-time.After(time.Millisecond * time.Duration(constdef.DEFAULT_EXPECT_TIMEOUT_STEP)):
-			unread := self.Reader.Buffered()
-			peekBuf, err := self.Reader.Peek(unread)
-			if err != nil {
-				return "", 0, errors.New(fmt.Sprintf("%s", err))
+			if match.Re.MatchString(tail) {
+				matched = true
+			} else if match.MultiLine && len(text) > 0 {
+				loc = match.Re.FindStringIndex(text)
 			}
-			line := vtclean.Clean(string(peekBuf), false)
-
-			for _, matchtable := range matchtable {
-				switch matchtable.MatchType {
-				case MATCH_TYPE_RE:
-					if matchtable.Re == nil {
-						return "", 0, errors.New("regexp is nil")
-					}
-					if matchtable.Re.Match([]byte(line)) {
-						return line, matchtable.LineType, nil
-					}
-				case MATCH_TYPE_STR_EXACT:
-					if line == matchtable.Str {
-						return line, matchtable.LineType, nil
-					}
-				case MATCH_TYPE_STR_CONTAIN:
-					if strings.Contains(line, matchtable.Str) {
-						return line, matchtable.LineType, nil
-					}
-				default:
-					return "", 0, errors.New("invalid prompt match type")
+		case MATCH_TYPE_STR_EXACT:
+			matched = (tail == match.Str)
+		case MATCH_TYPE_STR_CONTAIN:
+			if strings.Contains(tail, match.Str) {
+				matched = true
+			} else if match.MultiLine && len(text) > 0 {
+				if pos := strings.Index(text, match.Str); pos >= 0 {
+					loc = []int{pos, pos + len(match.Str)}
 				}
 			}
+		default:
+			return nil, 0, errors.New("invalid prompt match type")
+		}
 
-			if timeout > 0 {
-				timeoutCount -= time.Duration(constdef.DEFAULT_EXPECT_TIMEOUT_STEP)
-				if timeoutCount <= 0 {
-					return "", 0, errors.New("timeout")
-				}
+		/* 미완성 line 일치
+		 */
+		if matched {
+			return &StreamMatch{
+				LineType: match.LineType,
+				Index:    index,
+				Lines:    lines,
+				Match:    tail,
+			}, size, nil
+		}
+
+		if len(loc) == 0 {
+			continue
+		}
+
+		/* 여러 line 일치, 일치된 시작 line 부터 끝 line 까지 Match
+		 * 완성된 line 중 stream 에 남아있는 line 은 lineEnds 로 위치 계산
+		 */
+		startLine := strings.Count(text[:loc[0]], "\n")
+		endLine := startLine
+		if loc[1] > loc[0] {
+			endLine = strings.Count(text[:loc[1]-1], "\n")
+		}
+
+		consumed := size
+		matchStr := strings.Join(append(append([]string{}, lines...), tail)[startLine:endLine+1], "\n")
+		if endLine < len(lines) {
+			consumed = lineEnds[endLine]
+		}
+
+		return &StreamMatch{
+			LineType: match.LineType,
+			Index:    index,
+			Lines:    lines[:startLine],
+			Match:    matchStr,
+		}, consumed, nil
+	}
+
+	return nil, 0, nil
+}
+
+/* screen 매칭 조건
+ * Row 가 0 이상이면 Row, Col 위치에서 시작하는 text 비교, 아니면 screen 전체 비교
+ */
+type ScreenMatch struct {
+	MatchType uint8
+	Re        *regexp.Regexp // MATCH_TYPE_RE
+	Str       string         // MATCH_TYPE_STR_CONTAIN
+	Row       int
+	Col       int
+}
+
+/* screen 에 ScreenMatch 와 일치하는 text 가 나타날때까지 대기
+ * 일치하면 비교한 text, screen lines return, stream 의 받은 data 는 모두 삭제
+ * timeout(ms) 동안 screen 변경이 없으면 false
+ */
+func (self *PtyProcess) ExpectScreen(match *ScreenMatch, timeout time.Duration) (bool, string, []string, *errors.Error) {
+	if self.Screen == nil || match == nil {
+		return false, "", nil, errors.New("Invalid arguments")
+	}
+
+	if self.Monitor != nil {
+		return false, "", nil, errors.New("output is captured by monitor")
+	}
+
+	for {
+		/* screen 은 stream 에 쓴 다음 갱신되므로 stream 이 가득 차면 비워서 output 읽기가 멈추지 않게 함
+		 */
+		data, closed := self.Stream.Bytes()
+		if self.Stream.Full() {
+			self.Stream.Consume(len(data))
+		}
+		lines := self.Screen.Lines()
+
+		text := strings.Join(lines, "\n")
+		if match.Row >= 0 {
+			text = self.Screen.LineFrom(match.Row, match.Col)
+		}
+
+		matched := false
+		switch match.MatchType {
+		case MATCH_TYPE_RE:
+			if match.Re == nil {
+				return false, "", nil, errors.New("regexp is nil")
 			}
+			loc := match.Re.FindStringIndex(text)
+			matched = (loc != nil && (match.Row < 0 || loc[0] == 0))
+		case MATCH_TYPE_STR_CONTAIN:
+			if match.Row >= 0 {
+				matched = strings.HasPrefix(text, match.Str)
+			} else {
+				matched = strings.Contains(text, match.Str)
+			}
+		default:
+			return false, "", nil, errors.New("invalid screen match type")
+		}
+
+		if matched {
+			data, _ := self.Stream.Bytes()
+			self.Stream.Consume(len(data))
+			return true, text, lines, nil
+		}
+
+		if closed {
+			return false, "", nil, errors.New("OutputStream has closed.")
+		}
+
+		var timer <-chan time.Time = nil
+		if timeout > 0 {
+			timer = time.After(time.Millisecond * timeout)
+		}
+
+		select {
+		case <-self.Screen.Notify():
+		case <-self.Stream.Notify():
+		case <-timer:
+			return false, "", lines, nil
 		}
 	}
 }
@@ -182,7 +470,11 @@ func (self *PtyProcess) Write(rawMsg string) *errors.Error {
 	default:
 	}
 
-	_, goerr := self.Fp.Write([]byte(msg))
+	if self.Conn == nil {
+		return errors.New("process is not started")
+	}
+
+	_, goerr := self.Conn.Write([]byte(msg))
 	if goerr != nil {
 		return errors.New(fmt.Sprintf("%s", goerr))
 	}
@@ -191,6 +483,12 @@ func (self *PtyProcess) Write(rawMsg string) *errors.Error {
 }
 
 func (self *PtyProcess) GetReceivedBufferSize() (int, *errors.Error) {
+	/* transport 는 받은 data 를 바로 stream 으로 읽음
+	 */
+	if self.Transport != nil {
+		return 0, nil
+	}
+
 	if self.Fp == nil {
 		return -1, errors.New("Invalid fp")
 	}
@@ -206,6 +504,13 @@ func (self *PtyProcess) GetReceivedBufferSize() (int, *errors.Error) {
 }
 
 func (self *PtyProcess) Stop() {
+	self.Stream.Close()
+
+	if self.Transport != nil {
+		self.Transport.Close()
+		return
+	}
+
 	self.Fp.Close()
 	self.ExecCmd.Process.Kill()
 	self.ExecCmd.Wait()
@@ -0,0 +1,532 @@
+package proc
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+/* VT100/xterm screen parser 상태
+ */
+const (
+	SCREEN_STATE_GROUND = iota
+	SCREEN_STATE_ESCAPE
+	SCREEN_STATE_CSI
+	SCREEN_STATE_OSC
+	SCREEN_STATE_OSC_ESCAPE
+	SCREEN_STATE_CHARSET
+)
+
+const DEFAULT_SCREEN_ROWS = 24
+const DEFAULT_SCREEN_COLS = 80
+
+/* 2 cell 을 차지하는 wide char 의 두번째 cell
+ */
+const SCREEN_WIDE_CELL rune = 0
+
+/* full screen program(top, menuconfig, 설치 wizard 등) 을 위한 VT100/xterm screen grid
+ * XXX: utf8 만 지원, 색상/속성 무시
+ */
+type Screen struct {
+	mutex sync.Mutex
+
+	Rows int
+	Cols int
+
+	cells     [][]rune
+	mainCells [][]rune /* alternate screen 사용 중 main screen 보관 */
+
+	curRow      int
+	curCol      int
+	savedRow    int
+	savedCol    int
+	wrapPending bool
+
+	scrollTop    int
+	scrollBottom int
+
+	state   int
+	params  []byte
+	pending []byte /* 미완성 utf8 byte */
+
+	notify chan bool
+}
+
+func NewScreen(rows, cols int) *Screen {
+	if rows <= 0 {
+		rows = DEFAULT_SCREEN_ROWS
+	}
+	if cols <= 0 {
+		cols = DEFAULT_SCREEN_COLS
+	}
+
+	screen := Screen{
+		Rows:         rows,
+		Cols:         cols,
+		cells:        newScreenCells(rows, cols),
+		scrollTop:    0,
+		scrollBottom: rows - 1,
+		state:        SCREEN_STATE_GROUND,
+		notify:       make(chan bool, 1),
+	}
+
+	return &screen
+}
+
+func newScreenCells(rows, cols int) [][]rune {
+	cells := make([][]rune, rows)
+	for i := range cells {
+		cells[i] = newScreenRow(cols)
+	}
+	return cells
+}
+
+func newScreenRow(cols int) []rune {
+	row := make([]rune, cols)
+	for i := range row {
+		row[i] = ' '
+	}
+	return row
+}
+
+/* data 변경 알림 channel
+ */
+func (self *Screen) Notify() <-chan bool {
+	return self.notify
+}
+
+/* screen 을 line 단위로 return, line 끝 공백은 제거
+ */
+func (self *Screen) Lines() []string {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	lines := []string{}
+	for row := 0; row < self.Rows; row++ {
+		lines = append(lines, self.lineFrom(row, 0))
+	}
+	return lines
+}
+
+/* row 의 col 위치 부터 line string, 범위 밖이면 빈 string
+ */
+func (self *Screen) LineFrom(row, col int) string {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	return self.lineFrom(row, col)
+}
+
+func (self *Screen) lineFrom(row, col int) string {
+	if row < 0 || row >= self.Rows || col < 0 || col >= self.Cols {
+		return ""
+	}
+
+	var builder strings.Builder
+	for _, c := range self.cells[row][col:] {
+		if c == SCREEN_WIDE_CELL {
+			continue
+		}
+		builder.WriteRune(c)
+	}
+
+	return strings.TrimRight(builder.String(), " ")
+}
+
+/* cursor 위치 (0 부터 시작)
+ */
+func (self *Screen) Cursor() (int, int) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	return self.curRow, self.curCol
+}
+
+func (self *Screen) Write(data []byte) {
+	self.mutex.Lock()
+
+	buf := append(self.pending, data...)
+	self.pending = nil
+
+	for i := 0; i < len(buf); {
+		c := buf[i]
+
+		if self.state == SCREEN_STATE_GROUND && c >= utf8.RuneSelf {
+			if !utf8.FullRune(buf[i:]) {
+				self.pending = append([]byte{}, buf[i:]...)
+				break
+			}
+			r, size := utf8.DecodeRune(buf[i:])
+			self.putRune(r)
+			i += size
+			continue
+		}
+
+		self.processByte(c)
+		i++
+	}
+
+	self.mutex.Unlock()
+
+	select {
+	case self.notify <- true:
+	default:
+	}
+}
+
+func (self *Screen) processByte(c byte) {
+	switch self.state {
+	case SCREEN_STATE_GROUND:
+		self.processControl(c)
+	case SCREEN_STATE_ESCAPE:
+		self.processEscape(c)
+	case SCREEN_STATE_CSI:
+		if c >= 0x40 && c <= 0x7e {
+			self.processCsi(c)
+			self.state = SCREEN_STATE_GROUND
+		} else if c == 0x1b {
+			self.state = SCREEN_STATE_ESCAPE
+		} else {
+			self.params = append(self.params, c)
+		}
+	case SCREEN_STATE_OSC:
+		if c == 0x07 {
+			self.state = SCREEN_STATE_GROUND
+		} else if c == 0x1b {
+			self.state = SCREEN_STATE_OSC_ESCAPE
+		}
+	case SCREEN_STATE_OSC_ESCAPE, SCREEN_STATE_CHARSET:
+		self.state = SCREEN_STATE_GROUND
+	default:
+		self.state = SCREEN_STATE_GROUND
+	}
+}
+
+func (self *Screen) processControl(c byte) {
+	switch c {
+	case 0x1b:
+		self.state = SCREEN_STATE_ESCAPE
+	case '\r':
+		self.curCol = 0
+		self.wrapPending = false
+	case '\n', 0x0b, 0x0c:
+		self.lineFeed()
+	case '\b':
+		if self.curCol > 0 {
+			self.curCol--
+		}
+		self.wrapPending = false
+	case '\t':
+		self.curCol = (self.curCol/8 + 1) * 8
+		if self.curCol >= self.Cols {
+			self.curCol = self.Cols - 1
+		}
+	default:
+		if c >= 0x20 && c < 0x7f {
+			self.putRune(rune(c))
+		}
+	}
+}
+
+func (self *Screen) processEscape(c byte) {
+	self.state = SCREEN_STATE_GROUND
+
+	switch c {
+	case '[':
+		self.params = self.params[:0]
+		self.state = SCREEN_STATE_CSI
+	case ']':
+		self.state = SCREEN_STATE_OSC
+	case '(', ')', '*', '+':
+		self.state = SCREEN_STATE_CHARSET
+	case '7':
+		self.savedRow, self.savedCol = self.curRow, self.curCol
+	case '8':
+		self.curRow, self.curCol = self.savedRow, self.savedCol
+		self.wrapPending = false
+	case 'D':
+		self.lineFeed()
+	case 'E':
+		self.curCol = 0
+		self.lineFeed()
+	case 'M':
+		self.reverseIndex()
+	case 'c':
+		self.reset()
+	}
+}
+
+/* CSI parameter, 생략된 값은 defaultValue
+ */
+func (self *Screen) csiParams(defaultValue int) (bool, []int) {
+	paramStr := string(self.params)
+	private := strings.HasPrefix(paramStr, "?")
+	paramStr = strings.TrimLeft(paramStr, "?>=! ")
+
+	values := []int{}
+	for _, s := range strings.Split(paramStr, ";") {
+		n, goerr := strconv.Atoi(s)
+		if goerr != nil {
+			n = defaultValue
+		}
+		values = append(values, n)
+	}
+
+	return private, values
+}
+
+func (self *Screen) processCsi(final byte) {
+	private, values := self.csiParams(0)
+	n := values[0]
+	if n <= 0 {
+		n = 1
+	}
+
+	switch final {
+	case 'A':
+		self.moveCursor(self.curRow-n, self.curCol)
+	case 'B', 'e':
+		self.moveCursor(self.curRow+n, self.curCol)
+	case 'C', 'a':
+		self.moveCursor(self.curRow, self.curCol+n)
+	case 'D':
+		self.moveCursor(self.curRow, self.curCol-n)
+	case 'E':
+		self.moveCursor(self.curRow+n, 0)
+	case 'F':
+		self.moveCursor(self.curRow-n, 0)
+	case 'G', '`':
+		self.moveCursor(self.curRow, n-1)
+	case 'd':
+		self.moveCursor(n-1, self.curCol)
+	case 'H', 'f':
+		col := 1
+		if len(values) > 1 && values[1] > 0 {
+			col = values[1]
+		}
+		self.moveCursor(n-1, col-1)
+	case 'J':
+		self.eraseDisplay(values[0])
+	case 'K':
+		self.eraseLine(values[0])
+	case 'L':
+		self.insertLines(n)
+	case 'M':
+		self.deleteLines(n)
+	case 'P':
+		self.deleteChars(n)
+	case '@':
+		self.insertChars(n)
+	case 'X':
+		for i := 0; i < n && self.curCol+i < self.Cols; i++ {
+			self.cells[self.curRow][self.curCol+i] = ' '
+		}
+	case 'S':
+		self.scrollUp(self.scrollTop, self.scrollBottom, n)
+	case 'T':
+		self.scrollDown(self.scrollTop, self.scrollBottom, n)
+	case 'r':
+		top, bottom := 1, self.Rows
+		if values[0] > 0 {
+			top = values[0]
+		}
+		if len(values) > 1 && values[1] > 0 {
+			bottom = values[1]
+		}
+		if top < bottom && bottom <= self.Rows {
+			self.scrollTop, self.scrollBottom = top-1, bottom-1
+		}
+		self.moveCursor(0, 0)
+	case 's':
+		self.savedRow, self.savedCol = self.curRow, self.curCol
+	case 'u':
+		self.curRow, self.curCol = self.savedRow, self.savedCol
+	case 'h', 'l':
+		if private {
+			for _, mode := range values {
+				if mode == 47 || mode == 1047 || mode == 1049 {
+					self.setAltScreen(final == 'h')
+				}
+			}
+		}
+	}
+}
+
+func (self *Screen) moveCursor(row, col int) {
+	if row < 0 {
+		row = 0
+	}
+	if row >= self.Rows {
+		row = self.Rows - 1
+	}
+	if col < 0 {
+		col = 0
+	}
+	if col >= self.Cols {
+		col = self.Cols - 1
+	}
+
+	self.curRow, self.curCol = row, col
+	self.wrapPending = false
+}
+
+func (self *Screen) putRune(r rune) {
+	width := runeWidth(r)
+
+	if self.wrapPending || self.curCol+width > self.Cols {
+		self.curCol = 0
+		self.lineFeed()
+	}
+
+	self.cells[self.curRow][self.curCol] = r
+	if width == 2 {
+		self.cells[self.curRow][self.curCol+1] = SCREEN_WIDE_CELL
+	}
+
+	self.curCol += width
+	if self.curCol >= self.Cols {
+		self.curCol = self.Cols - 1
+		self.wrapPending = true
+	}
+}
+
+func (self *Screen) lineFeed() {
+	self.wrapPending = false
+	if self.curRow == self.scrollBottom {
+		self.scrollUp(self.scrollTop, self.scrollBottom, 1)
+	} else if self.curRow < self.Rows-1 {
+		self.curRow++
+	}
+}
+
+func (self *Screen) reverseIndex() {
+	self.wrapPending = false
+	if self.curRow == self.scrollTop {
+		self.scrollDown(self.scrollTop, self.scrollBottom, 1)
+	} else if self.curRow > 0 {
+		self.curRow--
+	}
+}
+
+/* top ~ bottom 영역을 n line 위로 scroll
+ */
+func (self *Screen) scrollUp(top, bottom, n int) {
+	for ; n > 0; n-- {
+		copy(self.cells[top:bottom], self.cells[top+1:bottom+1])
+		self.cells[bottom] = newScreenRow(self.Cols)
+	}
+}
+
+/* top ~ bottom 영역을 n line 아래로 scroll
+ */
+func (self *Screen) scrollDown(top, bottom, n int) {
+	for ; n > 0; n-- {
+		copy(self.cells[top+1:bottom+1], self.cells[top:bottom])
+		self.cells[top] = newScreenRow(self.Cols)
+	}
+}
+
+func (self *Screen) insertLines(n int) {
+	if self.curRow < self.scrollTop || self.curRow > self.scrollBottom {
+		return
+	}
+	self.scrollDown(self.curRow, self.scrollBottom, n)
+}
+
+func (self *Screen) deleteLines(n int) {
+	if self.curRow < self.scrollTop || self.curRow > self.scrollBottom {
+		return
+	}
+	self.scrollUp(self.curRow, self.scrollBottom, n)
+}
+
+func (self *Screen) insertChars(n int) {
+	row := self.cells[self.curRow]
+	for ; n > 0; n-- {
+		copy(row[self.curCol+1:], row[self.curCol:self.Cols-1])
+		row[self.curCol] = ' '
+	}
+}
+
+func (self *Screen) deleteChars(n int) {
+	row := self.cells[self.curRow]
+	for ; n > 0; n-- {
+		copy(row[self.curCol:], row[self.curCol+1:])
+		row[self.Cols-1] = ' '
+	}
+}
+
+/* 0: cursor 부터 끝까지, 1: 처음부터 cursor 까지, 2: 전체
+ */
+func (self *Screen) eraseLine(mode int) {
+	start, end := self.curCol, self.Cols
+	switch mode {
+	case 1:
+		start, end = 0, self.curCol+1
+	case 2:
+		start, end = 0, self.Cols
+	}
+
+	for i := start; i < end && i < self.Cols; i++ {
+		self.cells[self.curRow][i] = ' '
+	}
+}
+
+func (self *Screen) eraseDisplay(mode int) {
+	switch mode {
+	case 0:
+		self.eraseLine(0)
+		for row := self.curRow + 1; row < self.Rows; row++ {
+			self.cells[row] = newScreenRow(self.Cols)
+		}
+	case 1:
+		self.eraseLine(1)
+		for row := 0; row < self.curRow; row++ {
+			self.cells[row] = newScreenRow(self.Cols)
+		}
+	default:
+		self.cells = newScreenCells(self.Rows, self.Cols)
+	}
+}
+
+func (self *Screen) setAltScreen(enable bool) {
+	if enable {
+		if self.mainCells == nil {
+			self.mainCells = self.cells
+			self.cells = newScreenCells(self.Rows, self.Cols)
+		}
+		return
+	}
+
+	if self.mainCells != nil {
+		self.cells = self.mainCells
+		self.mainCells = nil
+	}
+}
+
+func (self *Screen) reset() {
+	self.cells = newScreenCells(self.Rows, self.Cols)
+	self.mainCells = nil
+	self.curRow, self.curCol = 0, 0
+	self.savedRow, self.savedCol = 0, 0
+	self.scrollTop, self.scrollBottom = 0, self.Rows-1
+	self.wrapPending = false
+}
+
+/* 한글, 한자 등 wide char 는 2 cell
+ */
+func runeWidth(r rune) int {
+	switch {
+	case r >= 0x1100 && r <= 0x115f,
+		r >= 0x2e80 && r <= 0xa4cf,
+		r >= 0xac00 && r <= 0xd7a3,
+		r >= 0xf900 && r <= 0xfaff,
+		r >= 0xfe30 && r <= 0xfe4f,
+		r >= 0xff00 && r <= 0xff60,
+		r >= 0xffe0 && r <= 0xffe6,
+		r >= 0x20000 && r <= 0x3fffd:
+		return 2
+	}
+	return 1
+}
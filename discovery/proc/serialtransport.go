@@ -0,0 +1,138 @@
+package proc
+
+import (
+	"discovery/errors"
+	"discovery/fmt"
+	"io"
+	"os"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+/* linux termios CRTSCTS, syscall 패키지에 정의 안됨
+ */
+const SERIAL_CRTSCTS = 0x80000000
+
+var SERIAL_BAUD_TABLE = map[int]uint32{
+	1200:   syscall.B1200,
+	2400:   syscall.B2400,
+	4800:   syscall.B4800,
+	9600:   syscall.B9600,
+	19200:  syscall.B19200,
+	38400:  syscall.B38400,
+	57600:  syscall.B57600,
+	115200: syscall.B115200,
+	230400: syscall.B230400,
+	460800: syscall.B460800,
+	921600: syscall.B921600,
+}
+
+var SERIAL_DATA_BITS_TABLE = map[int]uint32{
+	5: syscall.CS5,
+	6: syscall.CS6,
+	7: syscall.CS7,
+	8: syscall.CS8,
+}
+
+/* serial tty 를 직접 open 하는 transport
+ */
+type SerialTransport struct {
+	Device      string
+	Baud        int
+	DataBits    int
+	Parity      string /* none, odd, even */
+	StopBits    int    /* 1, 2 */
+	FlowControl string /* none, hardware(rtscts), software(xonxoff) */
+
+	file *os.File
+}
+
+func (self *SerialTransport) Open(rows, cols int) (io.ReadWriter, *errors.Error) {
+	if len(self.Device) == 0 {
+		return nil, errors.New("serial config. has invalid arguments")
+	}
+
+	termios, err := self.getTermios()
+	if err != nil {
+		return nil, err
+	}
+
+	file, goerr := os.OpenFile(self.Device, os.O_RDWR|syscall.O_NOCTTY, 0)
+	if goerr != nil {
+		return nil, errors.New(fmt.Sprintf("%s", goerr))
+	}
+
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, file.Fd(), uintptr(syscall.TCSETS), uintptr(unsafe.Pointer(termios)))
+	if errno != 0 {
+		file.Close()
+		return nil, errors.New(fmt.Sprintf("%s, %s", self.Device, errno))
+	}
+
+	self.file = file
+	return file, nil
+}
+
+/* raw mode termios 설정
+ */
+func (self *SerialTransport) getTermios() (*syscall.Termios, *errors.Error) {
+	baud, ok := SERIAL_BAUD_TABLE[self.Baud]
+	if !ok {
+		return nil, errors.New(fmt.Sprintf("'%d' is invalid baud rate", self.Baud))
+	}
+
+	dataBits, ok := SERIAL_DATA_BITS_TABLE[self.DataBits]
+	if !ok {
+		return nil, errors.New(fmt.Sprintf("'%d' is invalid data bits. 5, 6, 7, 8 can available", self.DataBits))
+	}
+
+	termios := syscall.Termios{
+		Cflag:  baud | dataBits | syscall.CREAD | syscall.CLOCAL,
+		Ispeed: baud,
+		Ospeed: baud,
+	}
+
+	switch strings.ToLower(strings.TrimSpace(self.Parity)) {
+	case "none", "":
+	case "odd":
+		termios.Cflag |= syscall.PARENB | syscall.PARODD
+		termios.Iflag |= syscall.INPCK
+	case "even":
+		termios.Cflag |= syscall.PARENB
+		termios.Iflag |= syscall.INPCK
+	default:
+		return nil, errors.New(fmt.Sprintf("'%s' is invalid parity. none, odd, even can available", self.Parity))
+	}
+
+	switch self.StopBits {
+	case 1:
+	case 2:
+		termios.Cflag |= syscall.CSTOPB
+	default:
+		return nil, errors.New(fmt.Sprintf("'%d' is invalid stop bits. 1, 2 can available", self.StopBits))
+	}
+
+	switch strings.ToLower(strings.TrimSpace(self.FlowControl)) {
+	case "none", "":
+	case "hardware", "rtscts":
+		termios.Cflag |= SERIAL_CRTSCTS
+	case "software", "xonxoff":
+		termios.Iflag |= syscall.IXON | syscall.IXOFF
+	default:
+		return nil, errors.New(fmt.Sprintf("'%s' is invalid flow control. none, hardware, software can available", self.FlowControl))
+	}
+
+	/* 1 byte 이상 수신되면 read return
+	 */
+	termios.Cc[syscall.VMIN] = 1
+	termios.Cc[syscall.VTIME] = 0
+
+	return &termios, nil
+}
+
+func (self *SerialTransport) Close() {
+	if self.file != nil {
+		self.file.Close()
+		self.file = nil
+	}
+}
@@ -0,0 +1,99 @@
+package proc
+
+import (
+	"github.com/creack/pty"
+	"io"
+	"syscall"
+	"testing"
+	"time"
+	"unsafe"
+)
+
+/* timeout 안에 size 만큼 read
+ */
+func readWithTimeout(t *testing.T, reader io.Reader, size int) string {
+	result := make(chan string, 1)
+	go func() {
+		buf := make([]byte, size)
+		n, _ := io.ReadFull(reader, buf)
+		result <- string(buf[:n])
+	}()
+
+	select {
+	case data := <-result:
+		return data
+	case <-time.After(time.Second * 5):
+		t.Fatal("read timeout")
+	}
+	return ""
+}
+
+/* pty pair 의 slave 를 serial device 로 사용
+ */
+func TestSerialTransportPty(t *testing.T) {
+	master, tty, goerr := pty.Open()
+	if goerr != nil {
+		t.Skip(goerr)
+	}
+	defer master.Close()
+	defer tty.Close()
+
+	transport := SerialTransport{
+		Device:   tty.Name(),
+		Baud:     115200,
+		DataBits: 8,
+		StopBits: 1,
+	}
+
+	conn, err := transport.Open(24, 80)
+	if err != nil {
+		t.Fatal(err.ToString(false))
+	}
+	defer transport.Close()
+
+	termios := syscall.Termios{}
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, tty.Fd(), uintptr(syscall.TCGETS), uintptr(unsafe.Pointer(&termios)))
+	if errno != 0 {
+		t.Fatal(errno)
+	}
+	if termios.Cflag&syscall.CSIZE != syscall.CS8 {
+		t.Errorf("cflag %#x, want CS8", termios.Cflag)
+	}
+	if termios.Lflag&(syscall.ECHO|syscall.ICANON) != 0 {
+		t.Errorf("lflag %#x, want raw mode", termios.Lflag)
+	}
+
+	/* raw mode 이므로 개행 변환, echo 없이 그대로 전달
+	 */
+	if _, goerr := conn.Write([]byte("hello\n")); goerr != nil {
+		t.Fatal(goerr)
+	}
+	if data := readWithTimeout(t, master, 6); data != "hello\n" {
+		t.Errorf("device read %q, want hello\\n", data)
+	}
+
+	if _, goerr := master.Write([]byte("world\r")); goerr != nil {
+		t.Fatal(goerr)
+	}
+	if data := readWithTimeout(t, conn, 6); data != "world\r" {
+		t.Errorf("transport read %q, want world\\r", data)
+	}
+}
+
+func TestSerialTransportInvalidConfig(t *testing.T) {
+	transports := []SerialTransport{
+		{Device: "/dev/null", Baud: 1000, DataBits: 8, StopBits: 1},
+		{Device: "/dev/null", Baud: 9600, DataBits: 9, StopBits: 1},
+		{Device: "/dev/null", Baud: 9600, DataBits: 8, StopBits: 3},
+		{Device: "/dev/null", Baud: 9600, DataBits: 8, StopBits: 1, Parity: "mark"},
+		{Device: "/dev/null", Baud: 9600, DataBits: 8, StopBits: 1, FlowControl: "dtr"},
+		{Baud: 9600, DataBits: 8, StopBits: 1},
+	}
+
+	for _, transport := range transports {
+		if _, err := transport.Open(24, 80); err == nil {
+			transport.Close()
+			t.Errorf("%+v, want error", transport)
+		}
+	}
+}
@@ -0,0 +1,273 @@
+package proc
+
+import (
+	"bytes"
+	"discovery/constdef"
+	"discovery/errors"
+	"discovery/fmt"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/* in-process ssh client transport
+ * /usr/bin/ssh 실행, prompt 에 password 전송 없이 ssh 프로토콜로 인증
+ */
+type SSHTransport struct {
+	Ip            string
+	Port          int
+	Username      string
+	Password      string
+	KeyFiles      []string
+	KeyPassphrase string
+	AgentFlag     bool
+	HostKey       string /* pinning 할 host key, "SHA256:..." fingerprint 또는 authorized_keys 형식 */
+	Keepalive     int    /* keepalive 주기(초), 0 이면 사용 안함 */
+	Timeout       int    /* 접속 timeout(초) */
+
+	client    *ssh.Client
+	session   *ssh.Session
+	agentConn net.Conn
+	stopChan  chan bool
+}
+
+/* ssh session 의 stdin, stdout
+ */
+type SSHConn struct {
+	io.Reader
+	io.Writer
+}
+
+func (self *SSHTransport) GetAddr() string {
+	return net.JoinHostPort(self.Ip, strconv.Itoa(self.Port))
+}
+
+func (self *SSHTransport) Open(rows, cols int) (io.ReadWriter, *errors.Error) {
+	if len(self.Ip) == 0 || len(self.Username) == 0 || self.Port <= 0 {
+		return nil, errors.New("ssh config. has invalid arguments")
+	}
+
+	authMethods, err := self.getAuthMethods()
+	if err != nil {
+		self.Close()
+		return nil, err
+	}
+
+	timeout := self.Timeout
+	if timeout <= 0 {
+		timeout = int(constdef.LOGIN_EXPECT_TIMEOUT)
+	}
+
+	clientConfig := &ssh.ClientConfig{
+		User:            self.Username,
+		Auth:            authMethods,
+		HostKeyCallback: self.checkHostKey,
+		Timeout:         time.Second * time.Duration(timeout),
+	}
+
+	/* 에러 return 시 client, session, agent 연결은 Close 로 정리
+	 */
+	client, goerr := ssh.Dial("tcp", self.GetAddr(), clientConfig)
+	if goerr != nil {
+		self.Close()
+		return nil, errors.New(fmt.Sprintf("%s", goerr))
+	}
+	self.client = client
+
+	session, goerr := client.NewSession()
+	if goerr != nil {
+		self.Close()
+		return nil, errors.New(fmt.Sprintf("%s", goerr))
+	}
+	self.session = session
+
+	stdin, goerr := session.StdinPipe()
+	if goerr != nil {
+		self.Close()
+		return nil, errors.New(fmt.Sprintf("%s", goerr))
+	}
+
+	stdout, goerr := session.StdoutPipe()
+	if goerr != nil {
+		self.Close()
+		return nil, errors.New(fmt.Sprintf("%s", goerr))
+	}
+
+	modes := ssh.TerminalModes{
+		ssh.ECHO:          1,
+		ssh.TTY_OP_ISPEED: 38400,
+		ssh.TTY_OP_OSPEED: 38400,
+	}
+
+	goerr = session.RequestPty("xterm", rows, cols, modes)
+	if goerr == nil {
+		goerr = session.Shell()
+	}
+	if goerr != nil {
+		self.Close()
+		return nil, errors.New(fmt.Sprintf("%s", goerr))
+	}
+
+	self.stopChan = make(chan bool)
+
+	if self.Keepalive > 0 {
+		go self.keepalive(client, self.stopChan, time.Second*time.Duration(self.Keepalive))
+	}
+
+	return &SSHConn{Reader: stdout, Writer: stdin}, nil
+}
+
+/* key file, agent, password, keyboard-interactive 순으로 인증
+ */
+func (self *SSHTransport) getAuthMethods() ([]ssh.AuthMethod, *errors.Error) {
+	authMethods := []ssh.AuthMethod{}
+
+	signers := []ssh.Signer{}
+	for _, keyFile := range self.KeyFiles {
+		keyFile = strings.TrimSpace(keyFile)
+		if len(keyFile) == 0 {
+			continue
+		}
+
+		if strings.HasPrefix(keyFile, "~/") {
+			keyFile = os.Getenv("HOME") + keyFile[1:]
+		}
+
+		data, goerr := os.ReadFile(keyFile)
+		if goerr != nil {
+			return nil, errors.New(fmt.Sprintf("%s", goerr))
+		}
+
+		var signer ssh.Signer
+		if len(self.KeyPassphrase) > 0 {
+			signer, goerr = ssh.ParsePrivateKeyWithPassphrase(data, []byte(self.KeyPassphrase))
+		} else {
+			signer, goerr = ssh.ParsePrivateKey(data)
+		}
+		if goerr != nil {
+			return nil, errors.New(fmt.Sprintf("%s, %s", keyFile, goerr))
+		}
+		signers = append(signers, signer)
+	}
+
+	if self.AgentFlag {
+		sock := os.Getenv("SSH_AUTH_SOCK")
+		if len(sock) == 0 {
+			return nil, errors.New("SSH_AUTH_SOCK is not set")
+		}
+
+		agentConn, goerr := net.Dial("unix", sock)
+		if goerr != nil {
+			return nil, errors.New(fmt.Sprintf("%s", goerr))
+		}
+		self.agentConn = agentConn
+
+		agentSigners, goerr := agent.NewClient(agentConn).Signers()
+		if goerr != nil {
+			return nil, errors.New(fmt.Sprintf("%s", goerr))
+		}
+		signers = append(signers, agentSigners...)
+	}
+
+	if len(signers) > 0 {
+		authMethods = append(authMethods, ssh.PublicKeys(signers...))
+	}
+
+	if len(self.Password) > 0 {
+		authMethods = append(authMethods, ssh.Password(self.Password))
+		authMethods = append(authMethods, ssh.KeyboardInteractive(self.answerKeyboardInteractive))
+	}
+
+	if len(authMethods) == 0 {
+		return nil, errors.New("ssh auth method is not set. password, key_file or agent")
+	}
+
+	return authMethods, nil
+}
+
+/* keyboard-interactive, echo 안되는 질문(password)에 password 응답
+ */
+func (self *SSHTransport) answerKeyboardInteractive(user, instruction string, questions []string, echos []bool) ([]string, error) {
+	answers := make([]string, len(questions))
+	for i := range questions {
+		if !echos[i] {
+			answers[i] = self.Password
+		}
+	}
+	return answers, nil
+}
+
+/* host key pinning
+ * HostKey 설정이 없으면 접속 실패, 에러에 fingerprint 출력
+ */
+func (self *SSHTransport) checkHostKey(hostname string, remote net.Addr, key ssh.PublicKey) error {
+	fingerprint := ssh.FingerprintSHA256(key)
+
+	pinned := strings.TrimSpace(self.HostKey)
+	if len(pinned) == 0 {
+		return fmt.Errorf("%s host key is not pinned. set host_key = %s", hostname, fingerprint)
+	}
+
+	if strings.HasPrefix(pinned, "SHA256:") {
+		if pinned == fingerprint {
+			return nil
+		}
+	} else {
+		pinnedKey, _, _, _, goerr := ssh.ParseAuthorizedKey([]byte(pinned))
+		if goerr != nil {
+			return goerr
+		}
+		if bytes.Equal(pinnedKey.Marshal(), key.Marshal()) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%s host key mismatch, %s", hostname, fingerprint)
+}
+
+/* Close 에서 self.stopChan 을 nil 로 변경하므로 client, stopChan 은 인자로 전달
+ */
+func (self *SSHTransport) keepalive(client *ssh.Client, stopChan chan bool, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			_, _, goerr := client.SendRequest("keepalive@openssh.com", true, nil)
+			if goerr != nil {
+				client.Close()
+				return
+			}
+		case <-stopChan:
+			return
+		}
+	}
+}
+
+func (self *SSHTransport) Close() {
+	if self.stopChan != nil {
+		close(self.stopChan)
+		self.stopChan = nil
+	}
+
+	if self.session != nil {
+		self.session.Close()
+		self.session = nil
+	}
+
+	if self.client != nil {
+		self.client.Close()
+		self.client = nil
+	}
+
+	if self.agentConn != nil {
+		self.agentConn.Close()
+		self.agentConn = nil
+	}
+}
@@ -0,0 +1,218 @@
+package proc
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"golang.org/x/crypto/ssh"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+/* loopback ssh server, password 와 clientKey 로 인증, shell 은 입력을 그대로 echo
+ */
+type testSSHServer struct {
+	listener net.Listener
+	hostKey  ssh.Signer
+}
+
+func newTestSSHServer(t *testing.T, password string, clientKey ssh.PublicKey) *testSSHServer {
+	hostKey := newTestSigner(t)
+
+	config := &ssh.ServerConfig{
+		PasswordCallback: func(conn ssh.ConnMetadata, pass []byte) (*ssh.Permissions, error) {
+			if string(pass) == password {
+				return nil, nil
+			}
+			return nil, io.EOF
+		},
+		PublicKeyCallback: func(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if clientKey != nil && string(key.Marshal()) == string(clientKey.Marshal()) {
+				return nil, nil
+			}
+			return nil, io.EOF
+		},
+	}
+	config.AddHostKey(hostKey)
+
+	listener, goerr := net.Listen("tcp", "127.0.0.1:0")
+	if goerr != nil {
+		t.Fatal(goerr)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, goerr := listener.Accept()
+			if goerr != nil {
+				return
+			}
+			go serveTestSSHConn(conn, config)
+		}
+	}()
+
+	return &testSSHServer{listener: listener, hostKey: hostKey}
+}
+
+func serveTestSSHConn(conn net.Conn, config *ssh.ServerConfig) {
+	_, chans, reqs, goerr := ssh.NewServerConn(conn, config)
+	if goerr != nil {
+		conn.Close()
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+
+	for newChan := range chans {
+		if newChan.ChannelType() != "session" {
+			newChan.Reject(ssh.UnknownChannelType, "")
+			continue
+		}
+
+		channel, requests, goerr := newChan.Accept()
+		if goerr != nil {
+			continue
+		}
+
+		go func() {
+			for req := range requests {
+				req.Reply(req.Type == "pty-req" || req.Type == "shell", nil)
+			}
+		}()
+		go func() {
+			io.Copy(channel, channel)
+			channel.Close()
+		}()
+	}
+}
+
+func (self *testSSHServer) transport() *SSHTransport {
+	addr := self.listener.Addr().(*net.TCPAddr)
+	return &SSHTransport{
+		Ip:       addr.IP.String(),
+		Port:     addr.Port,
+		Username: "tester",
+		Timeout:  5,
+	}
+}
+
+func newTestSigner(t *testing.T) ssh.Signer {
+	key, goerr := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if goerr != nil {
+		t.Fatal(goerr)
+	}
+
+	signer, goerr := ssh.NewSignerFromKey(key)
+	if goerr != nil {
+		t.Fatal(goerr)
+	}
+	return signer
+}
+
+func writeTestKeyFile(t *testing.T) (string, ssh.PublicKey) {
+	key, goerr := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if goerr != nil {
+		t.Fatal(goerr)
+	}
+
+	der, goerr := x509.MarshalECPrivateKey(key)
+	if goerr != nil {
+		t.Fatal(goerr)
+	}
+
+	path := filepath.Join(t.TempDir(), "id_ecdsa")
+	data := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})
+	if goerr := os.WriteFile(path, data, 0600); goerr != nil {
+		t.Fatal(goerr)
+	}
+
+	publicKey, goerr := ssh.NewPublicKey(&key.PublicKey)
+	if goerr != nil {
+		t.Fatal(goerr)
+	}
+	return path, publicKey
+}
+
+/* shell 에 보낸 문자열이 echo 되는지 확인
+ */
+func checkEcho(t *testing.T, transport *SSHTransport) {
+	conn, err := transport.Open(24, 80)
+	if err != nil {
+		t.Fatal(err.ToString(false))
+	}
+	defer transport.Close()
+
+	if _, goerr := conn.Write([]byte("hello")); goerr != nil {
+		t.Fatal(goerr)
+	}
+
+	buf := make([]byte, 5)
+	if _, goerr := io.ReadFull(conn, buf); goerr != nil {
+		t.Fatal(goerr)
+	}
+	if string(buf) != "hello" {
+		t.Errorf("echo = %q, want hello", buf)
+	}
+}
+
+func TestSSHTransportPassword(t *testing.T) {
+	server := newTestSSHServer(t, "secret", nil)
+
+	transport := server.transport()
+	transport.Password = "secret"
+	transport.HostKey = ssh.FingerprintSHA256(server.hostKey.PublicKey())
+	transport.Keepalive = 1
+	checkEcho(t, transport)
+
+	transport = server.transport()
+	transport.Password = "wrong"
+	transport.HostKey = ssh.FingerprintSHA256(server.hostKey.PublicKey())
+	if _, err := transport.Open(24, 80); err == nil {
+		transport.Close()
+		t.Error("wrong password, want error")
+	}
+}
+
+func TestSSHTransportKeyFile(t *testing.T) {
+	keyFile, publicKey := writeTestKeyFile(t)
+	server := newTestSSHServer(t, "", publicKey)
+
+	transport := server.transport()
+	transport.KeyFiles = []string{keyFile}
+	transport.HostKey = string(ssh.MarshalAuthorizedKey(server.hostKey.PublicKey()))
+	checkEcho(t, transport)
+}
+
+func TestSSHTransportHostKey(t *testing.T) {
+	server := newTestSSHServer(t, "secret", nil)
+	fingerprint := ssh.FingerprintSHA256(server.hostKey.PublicKey())
+
+	/* host_key 설정이 없으면 fingerprint 를 포함한 에러
+	 */
+	transport := server.transport()
+	transport.Password = "secret"
+	_, err := transport.Open(24, 80)
+	if err == nil {
+		transport.Close()
+		t.Fatal("host key is not pinned, want error")
+	}
+	if !strings.Contains(err.ToString(false), fingerprint) {
+		t.Errorf("error %q does not contain %s", err.ToString(false), fingerprint)
+	}
+
+	otherKey := newTestSigner(t).PublicKey()
+	for _, hostKey := range []string{ssh.FingerprintSHA256(otherKey), string(ssh.MarshalAuthorizedKey(otherKey))} {
+		transport = server.transport()
+		transport.Password = "secret"
+		transport.HostKey = hostKey
+		if _, err := transport.Open(24, 80); err == nil {
+			transport.Close()
+			t.Errorf("host key %s mismatch, want error", hostKey)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package proc
+
+import (
+	"discovery/constdef"
+	"github.com/lunixbochs/vtclean"
+	"strings"
+	"sync"
+)
+
+/* pty output byte stream buffer
+ * reader goroutine 이 data 를 추가하면 대기중인 matcher 에 바로 알림
+ * buffer 가 MaxSize 이상이면 consume 될때까지 Write 대기
+ */
+type OutputStream struct {
+	MaxSize int
+
+	mutex    sync.Mutex
+	consumed *sync.Cond
+	buffer   []byte
+	notify   chan bool
+	closed   bool
+}
+
+func NewOutputStream() *OutputStream {
+	stream := OutputStream{
+		MaxSize: constdef.OUTPUT_STREAM_MAX_SIZE,
+		buffer:  []byte{},
+		notify:  make(chan bool, 1),
+		closed:  false,
+	}
+	stream.consumed = sync.NewCond(&stream.mutex)
+
+	return &stream
+}
+
+func (self *OutputStream) Write(data []byte) {
+	self.mutex.Lock()
+	for len(self.buffer) >= self.MaxSize && !self.closed {
+		self.consumed.Wait()
+	}
+
+	if !self.closed {
+		self.buffer = append(self.buffer, data...)
+	}
+	self.mutex.Unlock()
+
+	self.wakeup()
+}
+
+/* close 이후 Write 는 무시, 대기중인 Write 도 종료
+ */
+func (self *OutputStream) Close() {
+	self.mutex.Lock()
+	self.closed = true
+	self.consumed.Broadcast()
+	self.mutex.Unlock()
+
+	self.wakeup()
+}
+
+func (self *OutputStream) wakeup() {
+	select {
+	case self.notify <- true:
+	default:
+	}
+}
+
+/* data 추가, close 알림 channel
+ */
+func (self *OutputStream) Notify() <-chan bool {
+	return self.notify
+}
+
+/* 읽지 않은 data 복사본, close 여부
+ */
+func (self *OutputStream) Bytes() ([]byte, bool) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	data := make([]byte, len(self.buffer))
+	copy(data, self.buffer)
+	return data, self.closed
+}
+
+/* buffer 가 MaxSize 이상이면 true
+ * 완성된 line 없이 가득 찬 경우 전체를 line 으로 처리하도록 사용
+ */
+func (self *OutputStream) Full() bool {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	return len(self.buffer) >= self.MaxSize
+}
+
+/* 앞에서 부터 size 만큼 삭제
+ */
+func (self *OutputStream) Consume(size int) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	if size <= 0 {
+		return
+	}
+
+	if size >= len(self.buffer) {
+		self.buffer = self.buffer[:0]
+	} else {
+		self.buffer = append([]byte{}, self.buffer[size:]...)
+	}
+	self.consumed.Broadcast()
+}
+
+/* data 가 들어올때까지 대기 후 전체 data return
+ * close 되고 남은 data 가 없으면 false
+ */
+func (self *OutputStream) Next() ([]byte, bool) {
+	for {
+		data, closed := self.Bytes()
+		if len(data) > 0 {
+			self.Consume(len(data))
+			return data, true
+		}
+
+		if closed {
+			return nil, false
+		}
+
+		<-self.notify
+	}
+}
+
+/* stream data 를 line 단위로 분리
+ * 완성된 line, 마지막 미완성 line(tail), 각 완성된 line 의 다음 line 시작 offset return
+ */
+func splitStreamLines(data []byte) ([]string, string, []int) {
+	lines := []string{}
+	lineEnds := []int{}
+
+	start := 0
+	for i, c := range data {
+		if c != '\n' {
+			continue
+		}
+		lines = append(lines, vtclean.Clean(strings.TrimRight(string(data[start:i]), "\r"), false))
+		lineEnds = append(lineEnds, i+1)
+		start = i + 1
+	}
+
+	tail := vtclean.Clean(string(data[start:]), false)
+	return lines, tail, lineEnds
+}
+
+/* splitStreamLines 와 같음, buffer 가 가득 찼는데 완성된 line 이 없으면 전체를 하나의 line 으로 처리
+ */
+func splitFullStreamLines(data []byte, full bool) ([]string, string, []int) {
+	lines, tail, lineEnds := splitStreamLines(data)
+	if full && len(lineEnds) == 0 && len(data) > 0 {
+		return []string{tail}, "", []int{len(data)}
+	}
+	return lines, tail, lineEnds
+}
@@ -5,6 +5,7 @@ import (
 	"discovery/errors"
 	"discovery/fmt"
 	"discovery/utils"
+	"github.com/alecthomas/participle/lexer"
 	"github.com/alecthomas/repr"
 	"strings"
 )
@@ -277,6 +278,8 @@ func (self *BPLogout) Do(context *ReplayerContext) *errors.Error {
 const BPRcmdStr = "bp"
 
 type BP struct {
+	Pos lexer.Position
+
 	Name    string     `@"bp"`
 	Login   *BPLogin   `(@@`
 	Rfc2544 *BPRfc2544 `|@@`
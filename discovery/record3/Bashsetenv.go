@@ -5,6 +5,7 @@ import (
 	"discovery/errors"
 	"discovery/fmt"
 	"discovery/utils"
+	"github.com/alecthomas/participle/lexer"
 	"github.com/alecthomas/repr"
 	"strings"
 )
@@ -12,6 +13,8 @@ import (
 const BashsetenvRcmdStr = "bashsetenv"
 
 type Bashsetenv struct {
+	Pos lexer.Position
+
 	Name        string `@"bashsetenv"`
 	IniName     string `@STRING`
 	SessionName string `@IDENT`
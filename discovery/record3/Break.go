@@ -2,6 +2,7 @@ package record3
 
 import (
 	"discovery/errors"
+	"github.com/alecthomas/participle/lexer"
 	"github.com/alecthomas/repr"
 )
 
@@ -16,6 +17,8 @@ const (
 const BreakRcmdStr = "break"
 
 type Break struct {
+	Pos lexer.Position
+
 	Name string `@"break"`
 }
 
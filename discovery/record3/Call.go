@@ -0,0 +1,158 @@
+package record3
+
+import (
+	"discovery/constdef"
+	"discovery/errors"
+	"discovery/fmt"
+	"github.com/alecthomas/participle/lexer"
+	"github.com/alecthomas/repr"
+)
+
+const CallRcmdStr = "call"
+
+/* proc 호출, VarName 이 있으면 return 값을 변수로 저장
+ */
+type Call struct {
+	Pos lexer.Position
+
+	Name     string        `@"call"`
+	ProcName string        `@IDENT`
+	Args     []*Expression `"(" [ @@ { "," @@ } ] ")"`
+	VarName  string        `[ @IDENT ]`
+}
+
+func NewCall(text string) (*Call, *errors.Error) {
+	target, err := NewStruct(text, &Call{})
+	if err != nil {
+		return nil, err
+	}
+	return target.(*Call), nil
+}
+
+func (self *Call) ToString() string {
+	text := self.Name + " " + self.ProcName + "("
+	for i, arg := range self.Args {
+		if i > 0 {
+			text += ", "
+		}
+		text += arg.ToString()
+	}
+	text += ")"
+
+	if len(self.VarName) > 0 {
+		text += " " + self.VarName
+	}
+	return text
+}
+
+func (self *Call) Prepare(context *ReplayerContext) *errors.Error {
+	return nil
+}
+
+func (self *Call) Do(context *ReplayerContext) (Void, *errors.Error) {
+	if context == nil {
+		return nil, errors.New("Invalid arguments").AddMsg(self.ToString())
+	}
+
+	proc, err := context.GetProc(self.ProcName)
+	if err != nil {
+		return nil, err.AddMsg(self.ToString())
+	}
+
+	if len(proc.Params) != len(self.Args) {
+		return nil, errors.New(fmt.Sprintf("'%s' proc needs %d arguments, but %d given",
+			self.ProcName, len(proc.Params), len(self.Args))).AddMsg(self.ToString())
+	}
+
+	if context.ProcDepth >= constdef.MAX_PROC_CALL_DEPTH {
+		return nil, errors.New(fmt.Sprintf("proc call depth exceeded %d", constdef.MAX_PROC_CALL_DEPTH)).AddMsg(self.ToString())
+	}
+
+	/* argument 는 호출한 위치의 변수로 계산
+	 */
+	varmap := NewVariableMap()
+	for i, arg := range self.Args {
+		value, err := arg.Do(context)
+		if err != nil {
+			return nil, err.AddMsg(self.ToString())
+		}
+
+		vari, err := NewVariable(proc.Params[i], value, "")
+		if err != nil {
+			return nil, err.AddMsg(self.ToString())
+		}
+
+		err = varmap.SetValue(vari)
+		if err != nil {
+			return nil, err.AddMsg(self.ToString())
+		}
+	}
+
+	err = proc.Prepare(context)
+	if err != nil {
+		return nil, err.AddMsg(self.ToString())
+	}
+
+	returnValue, controlflow, err := self.play(proc, varmap, context)
+	if err != nil {
+		return nil, err.AddMsg(self.ToString())
+	}
+
+	/* return 이 아닌 CF_RETURN 은 check, script 등의 record 중단이므로 상위로 전달
+	 */
+	if controlflow != nil {
+		return controlflow, nil
+	}
+
+	if len(self.VarName) > 0 {
+		err = context.SetVariable(self.VarName, returnValue, "")
+		if err != nil {
+			return nil, err.AddMsg(self.ToString())
+		}
+	}
+
+	return nil, nil
+}
+
+/* proc body 는 local var map 을 push 하여 수행
+ */
+func (self *Call) play(proc *Proc, varmap VariableMap, context *ReplayerContext) (Void, Void, *errors.Error) {
+	context.PushVarMapSlice(varmap)
+	context.ProcDepth += 1
+	defer func() {
+		context.ProcDepth -= 1
+		context.PopVarMapSlice()
+	}()
+
+	controlflow, err := PlayRcmdList(proc.RcmdObjList, context)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch controlflow.(type) {
+	case int:
+		switch controlflow.(int) {
+		case CF_BREAK, CF_CONTINUE:
+			return nil, nil, errors.New("break, continue can not be used outside of the loop in proc")
+		case CF_RETURN:
+			if !context.ProcReturnFlag {
+				return nil, controlflow, nil
+			}
+
+			returnValue := context.ProcReturnValue
+			context.ProcReturnFlag = false
+			context.ProcReturnValue = nil
+			return returnValue, nil, nil
+		}
+	}
+
+	return nil, nil, nil
+}
+
+func (self *Call) GetName() string {
+	return self.Name
+}
+
+func (self *Call) Dump() {
+	repr.Println(self)
+}
@@ -4,12 +4,15 @@ import (
 	"discovery/constdef"
 	"discovery/errors"
 	"discovery/fmt"
+	"github.com/alecthomas/participle/lexer"
 	"github.com/alecthomas/repr"
 )
 
 const CheckRcmdStr = "check"
 
 type Check struct {
+	Pos lexer.Position
+
 	Name     string      `@"check"`
 	Expr     *Expression `@@`
 	StepFlag bool        `[ [@"step"] ]` // check success/fail result 는 앞 comment에 붙여 출력 하지 않도록 함
@@ -80,6 +83,7 @@ func (self *Check) Do(context *ReplayerContext) (Void, *errors.Error) {
 	if err1 != nil {
 		return nil, err1.AddMsg(self.ToString())
 	}
+	chkResult.Position = GetPositionString(self.Pos)
 
 	/* FAIL
 	 */
@@ -3,12 +3,15 @@ package record3
 import (
 	"discovery/errors"
 	"discovery/fmt"
+	"github.com/alecthomas/participle/lexer"
 	"github.com/alecthomas/repr"
 )
 
 const CloseRcmdStr = "close"
 
 type Close struct {
+	Pos lexer.Position
+
 	Name        string `@"close"`
 	SessionName string `@IDENT`
 }
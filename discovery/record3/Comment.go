@@ -3,6 +3,7 @@ package record3
 import (
 	"discovery/errors"
 	"discovery/fmt"
+	"github.com/alecthomas/participle/lexer"
 	"github.com/alecthomas/repr"
 	"strings"
 )
@@ -10,6 +11,8 @@ import (
 const CommentRcmdStr = ";"
 
 type Comment struct {
+	Pos lexer.Position
+
 	Name string `@COMMENT`
 }
 
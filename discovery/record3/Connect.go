@@ -7,6 +7,7 @@ import (
 	"discovery/fmt"
 	"discovery/proc"
 	"discovery/utils"
+	"github.com/alecthomas/participle/lexer"
 	"github.com/alecthomas/repr"
 	"time"
 )
@@ -14,6 +15,8 @@ import (
 const ConnectRcmdStr = "connect"
 
 type Connect struct {
+	Pos lexer.Position
+
 	Name        string `@"connect"`
 	NodeName    string `@STRING`
 	SessionName string `@IDENT`
@@ -79,7 +82,7 @@ func (self *Connect) Do(context *ReplayerContext) (Void, *errors.Error) {
 	var promptstr string
 	for {
 		connectTry += 1
-		proc, promptstr, err = doConnect(node, context.OutputPrintFlag)
+		proc, promptstr, err = doConnectChain(context.Env, node, context.OutputPrintFlag)
 		if err != nil {
 			if connectTry >= 3 {
 				return nil, err.AddMsg(self.ToString())
@@ -110,7 +113,7 @@ func (self *Connect) Do2(env *config.Env) (*proc.PtyProcess, string, *errors.Err
 		return nil, "", errors.New(utils.Unquote(self.NodeName) + " node doesn't exist.").AddMsg(self.ToString())
 	}
 
-	proc, promptstr, err := doConnect(node, true)
+	proc, promptstr, err := doConnectChain(env, node, true)
 	if err != nil {
 		return nil, "", err.AddMsg(self.ToString())
 	}
@@ -128,11 +131,6 @@ func doConnect(node *config.Node, outputPrintFlag bool) (*proc.PtyProcess, strin
 	}
 	nodeinfo := node.NodeInfo
 
-	command, err := nodeinfo.GetConnectCommand()
-	if err != nil {
-		return nil, "", err
-	}
-
 	eol, err := nodeinfo.GetString("Eol")
 	if err != nil {
 		return nil, "", err
@@ -143,24 +141,106 @@ func doConnect(node *config.Node, outputPrintFlag bool) (*proc.PtyProcess, strin
 		return nil, "", err
 	}
 
-	proc, err := proc.NewPtyProcess(command, characterSet, eol)
+	/* in-process transport 지원하는 경우 transport 사용
+	 */
+	var transport proc.Transport = nil
+	if transportNode, ok := nodeinfo.(config.TransportInterface); ok {
+		transport, err = transportNode.GetTransport()
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	var ptyprocess *proc.PtyProcess
+	if transport != nil {
+		ptyprocess, err = proc.NewTransportProcess(node.Name, transport, characterSet, eol)
+	} else {
+		command, err1 := nodeinfo.GetConnectCommand()
+		if err1 != nil {
+			return nil, "", err1
+		}
+		ptyprocess, err = proc.NewPtyProcess(command, characterSet, eol)
+	}
 	if err != nil {
 		return nil, "", err
 	}
 
-	err = proc.Start()
+	err = ptyprocess.Start()
 	if err != nil {
 		return nil, "", err
 	}
 
 	/* 자동 로그인 처리
 	 */
-	promptstr, err := doAutoLogin(proc, node, outputPrintFlag)
+	promptstr, err := doAutoLogin(ptyprocess, node, outputPrintFlag)
 	if err != nil {
+		ptyprocess.Stop()
 		return nil, "", err
 	}
 
-	return proc, promptstr, nil
+	return ptyprocess, promptstr, nil
+}
+
+/* via 로 설정된 jump host 부터 순서대로 접속
+ * 첫 node 는 doConnect, 다음 node 부터는 이전 session 에서 접속 command 실행 후 자동 로그인
+ */
+func doConnectChain(env *config.Env, node *config.Node, outputPrintFlag bool) (*proc.PtyProcess, string, *errors.Error) {
+	if env == nil || node == nil {
+		return nil, "", errors.New("Invalid arguments")
+	}
+
+	chain, err := env.GetNodeChain(node.Name)
+	if err != nil {
+		return nil, "", err
+	}
+
+	ptyprocess, promptstr, err := doConnect(chain[0], outputPrintFlag)
+	if err != nil {
+		return nil, "", err
+	}
+
+	for _, hop := range chain[1:] {
+		promptstr, err = doHop(ptyprocess, hop, outputPrintFlag)
+		if err != nil {
+			ptyprocess.Stop()
+			return nil, "", err.AddMsg(fmt.Sprintf("%s node via %s", hop.Name, hop.Via))
+		}
+	}
+
+	return ptyprocess, promptstr, nil
+}
+
+/* 접속된 session 에서 node 로 접속
+ */
+func doHop(ptyprocess *proc.PtyProcess, node *config.Node, outputPrintFlag bool) (string, *errors.Error) {
+	nodeinfo := node.NodeInfo
+
+	eol, err := nodeinfo.GetString("Eol")
+	if err != nil {
+		return "", err
+	}
+
+	characterSet, err := nodeinfo.GetString("CharacterSet")
+	if err != nil {
+		return "", err
+	}
+
+	command, err := nodeinfo.GetConnectCommand()
+	if err != nil {
+		return "", err
+	}
+
+	err = ptyprocess.Write(command + ptyprocess.Eol)
+	if err != nil {
+		return "", err
+	}
+
+	err = ptyprocess.SetCharacterSetEol(characterSet, eol)
+	if err != nil {
+		return "", err
+	}
+
+	return doAutoLogin(ptyprocess, node, outputPrintFlag)
 }
 
 func (self *Connect) GetName() string {
@@ -2,12 +2,15 @@ package record3
 
 import (
 	"discovery/errors"
+	"github.com/alecthomas/participle/lexer"
 	"github.com/alecthomas/repr"
 )
 
 const ContinueRcmdStr = "continue"
 
 type Continue struct {
+	Pos lexer.Position
+
 	Name string `@"continue"`
 }
 
@@ -2,7 +2,7 @@ package record3
 
 import (
 	"discovery/errors"
-	"discovery/fmt"
+	"github.com/alecthomas/participle/lexer"
 	"github.com/alecthomas/repr"
 )
 
@@ -13,6 +13,8 @@ const DebugRcmdStr = "debug"
  * option 에 따라 선별적 출력
  */
 type Debug struct {
+	Pos lexer.Position
+
 	Name string `@"debug"`
 }
 
@@ -37,8 +39,8 @@ func (self *Debug) Do(context *ReplayerContext) (Void, *errors.Error) {
 	}
 
 	debugMsg := context.DumpToString()
-	fmt.Println("\n>>> DEBUG, CURRENT CONTEXT:")
-	fmt.Println(debugMsg)
+	context.GetPrinter().Println("\n>>> DEBUG, CURRENT CONTEXT:")
+	context.GetPrinter().Println(debugMsg)
 
 	return nil, nil
 }
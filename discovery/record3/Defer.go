@@ -2,12 +2,15 @@ package record3
 
 import (
 	"discovery/errors"
+	"github.com/alecthomas/participle/lexer"
 	"github.com/alecthomas/repr"
 )
 
 const DeferRcmdStr = "defer"
 
 type Defer struct {
+	Pos lexer.Position
+
 	Name            string    `@"defer"`
 	RcmdList        *RcmdList `@@`
 	EnddeferKeyword string    `@"enddefer"`
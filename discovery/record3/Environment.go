@@ -5,12 +5,15 @@ import (
 	"discovery/errors"
 	"discovery/fmt"
 	"discovery/utils"
+	"github.com/alecthomas/participle/lexer"
 	"github.com/alecthomas/repr"
 )
 
 const EnvironmentRcmdStr = "environment"
 
 type Environment struct {
+	Pos lexer.Position
+
 	Name    string `@"environment"`
 	EnvId   string `@STRING`
 	EnvHash string `@STRING`
@@ -53,46 +56,11 @@ func (self *Environment) Prepare(context *ReplayerContext) *errors.Error {
 	if self.Env == nil {
 		envid := utils.Unquote(self.EnvId)
 		if context != nil && len(context.ForceEnvId) > 0 {
-			forceEnvName, forceEnvCate, err := utils.ParseEnvId(context.ForceEnvId)
+			forceEnvId, err := GetForceEnvId(envid, context.ForceEnvId)
 			if err != nil {
 				return err
 			}
-
-			envName, envCate, err := utils.ParseRid(envid)
-			if err != nil {
-				return err
-			}
-
-			/* force envid 가 /single_route 이면 기존 카테고리는 보존하고 env name만 변경
-			 */
-			if context.ForceEnvId[0] == '/' ||
-				context.ForceEnvId[0] == ',' ||
-				context.ForceEnvId[0] == ';' ||
-				context.ForceEnvId[0] == ':' {
-
-				if len(forceEnvName) > 0 {
-					envName = forceEnvName
-				} else {
-					return errors.New(fmt.Sprintf("%s, invalid force env id", context.ForceEnvId))
-				}
-				/* force envid 가 rss_single_route/ 이면 기존 env name는 보존하고 env category만 변경
-				 */
-			} else if context.ForceEnvId[len(context.ForceEnvId)-1] == '/' ||
-				context.ForceEnvId[len(context.ForceEnvId)-1] == ',' ||
-				context.ForceEnvId[len(context.ForceEnvId)-1] == ';' ||
-				context.ForceEnvId[len(context.ForceEnvId)-1] == ':' {
-
-				if len(forceEnvCate) > 0 {
-					envCate = forceEnvCate
-				} else {
-					return errors.New(fmt.Sprintf("%s, invalid force env id", context.ForceEnvId))
-				}
-			} else {
-				envCate = forceEnvCate
-				envName = forceEnvName
-			}
-
-			envid = utils.Rid(envName, envCate)
+			envid = forceEnvId
 		}
 
 		env, err := config.NewEnv(envid)
@@ -112,6 +80,51 @@ func (self *Environment) Prepare(context *ReplayerContext) *errors.Error {
 	return nil
 }
 
+/* -env 옵션으로 override 된 env id
+ */
+func GetForceEnvId(envid string, forceEnvId string) (string, *errors.Error) {
+	forceEnvName, forceEnvCate, err := utils.ParseEnvId(forceEnvId)
+	if err != nil {
+		return "", err
+	}
+
+	envName, envCate, err := utils.ParseRid(envid)
+	if err != nil {
+		return "", err
+	}
+
+	/* force envid 가 /single_route 이면 기존 카테고리는 보존하고 env name만 변경
+	 */
+	if forceEnvId[0] == '/' ||
+		forceEnvId[0] == ',' ||
+		forceEnvId[0] == ';' ||
+		forceEnvId[0] == ':' {
+
+		if len(forceEnvName) > 0 {
+			envName = forceEnvName
+		} else {
+			return "", errors.New(fmt.Sprintf("%s, invalid force env id", forceEnvId))
+		}
+		/* force envid 가 rss_single_route/ 이면 기존 env name는 보존하고 env category만 변경
+		 */
+	} else if forceEnvId[len(forceEnvId)-1] == '/' ||
+		forceEnvId[len(forceEnvId)-1] == ',' ||
+		forceEnvId[len(forceEnvId)-1] == ';' ||
+		forceEnvId[len(forceEnvId)-1] == ':' {
+
+		if len(forceEnvCate) > 0 {
+			envCate = forceEnvCate
+		} else {
+			return "", errors.New(fmt.Sprintf("%s, invalid force env id", forceEnvId))
+		}
+	} else {
+		envCate = forceEnvCate
+		envName = forceEnvName
+	}
+
+	return utils.Rid(envName, envCate), nil
+}
+
 func (self *Environment) Do(context *ReplayerContext) (Void, *errors.Error) {
 	if context == nil {
 		return nil, errors.New("invalid arguments").AddMsg(self.ToString())
@@ -125,6 +138,15 @@ func (self *Environment) Do(context *ReplayerContext) (Void, *errors.Error) {
 		return nil, errors.New(fmt.Sprintf("%s environment is not init.", utils.Unquote(self.EnvId))).AddMsg(self.ToString())
 	}
 
+	/* 다른 replayer 와 env 를 동시에 사용하지 않도록 lease 획득
+	 */
+	lease, err := config.AcquireLease(utils.Rid(self.Env.EnvName, self.Env.EnvCategory),
+		utils.Rid(context.RecordName, context.RecordCategory), context.LockWait, context.ParentEnvLease)
+	if err != nil {
+		return nil, err.AddMsg(self.ToString())
+	}
+	context.EnvLease = lease
+
 	context.Env = self.Env
 
 	/* env node 정보 varMap에 load
@@ -4,6 +4,7 @@ import (
 	"discovery/constdef"
 	"discovery/errors"
 	"discovery/fmt"
+	"github.com/alecthomas/participle/lexer"
 	"github.com/alecthomas/repr"
 	"strings"
 )
@@ -11,6 +12,8 @@ import (
 const EolRcmdStr = "eol"
 
 type Eol struct {
+	Pos lexer.Position
+
 	Name        string `@"eol"`
 	Eol         string `@("lf" | "cr" | "crlf")`
 	SessionName string `@IDENT`
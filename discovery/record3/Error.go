@@ -4,12 +4,15 @@ import (
 	"discovery/errors"
 	"discovery/fmt"
 	"discovery/utils"
+	"github.com/alecthomas/participle/lexer"
 	"github.com/alecthomas/repr"
 )
 
 const ErrorRcmdStr = "error"
 
 type Error struct {
+	Pos lexer.Position
+
 	Name    string `@"error"`
 	Message string `@STRING`
 }
@@ -6,6 +6,7 @@ import (
 	"discovery/fmt"
 	"discovery/proc"
 	"discovery/utils"
+	"github.com/alecthomas/participle/lexer"
 	"github.com/alecthomas/repr"
 	"golang.org/x/text/encoding/korean"
 	"golang.org/x/text/transform"
@@ -18,6 +19,8 @@ import (
 const ExpectRcmdStr = "expect"
 
 type Expect struct {
+	Pos lexer.Position
+
 	Name          string  `@"expect"`
 	ExpectReFlag  bool    `[ @"r" ]`
 	ExpectStr     string  `@STRING`
@@ -63,7 +66,7 @@ func (self *Expect) Do(context *ReplayerContext) (Void, *errors.Error) {
 	}
 
 	matched, promptStr, outputLines, err := DoExpect(proc, self.ExpectTimeout, self.ExpectReFlag,
-		expectStr, context.OutputPrintFlag, constdef.MAX_OUTPUT_LINE_COUNT, context.LastPromptStr, true)
+		expectStr, context.OutputPrintFlag, context.GetPrinter(), constdef.MAX_OUTPUT_LINE_COUNT, context.LastPromptStr, true)
 
 	if err != nil {
 		return nil, err.AddMsg(self.ToString())
@@ -75,18 +78,94 @@ func (self *Expect) Do(context *ReplayerContext) (Void, *errors.Error) {
 		return nil, nil
 	}
 
+	err = setExpectOutput(context, sessionnode, self.SessionName, promptStr, outputLines)
+	if err != nil {
+		return nil, err.AddMsg(self.ToString())
+	}
+
+	err = setExpectMatchVariable(context, &ExpectPattern{ReFlag: self.ExpectReFlag, Str: expectStr}, promptStr, outputLines)
+	if err != nil {
+		return nil, err.AddMsg(self.ToString())
+	}
+
+	return nil, nil
+}
+
+/* expect pattern 의 capture group 을 map 변수(expect_match)로 저장
+ */
+func setExpectMatchVariable(context *ReplayerContext, pattern *ExpectPattern, promptStr string, outputLines []string) *errors.Error {
+	matchMap := make(map[Void]Void)
+
+	if pattern != nil {
+		re, err := pattern.Regexp()
+		if err != nil {
+			return err
+		}
+		matchMap = GetExpectMatch(re, promptStr, outputLines)
+	}
+
+	return context.SetVariable(constdef.EXPECT_MATCH_VARIABLE_NAME, matchMap, "")
+}
+
+/* regexp capture group 을 map 으로 return
+ * output lines, prompt 순으로 처음 일치하는 line 하나의 group 값 저장
+ * (?m), (?s) 의 multi line pattern 은 matcher 와 같이 output lines, prompt 를 "\n" 으로 합친 text 와 비교
+ * 번호 group 은 number key, 이름 group 은 string key
+ */
+func GetExpectMatch(re *regexp.Regexp, promptStr string, outputLines []string) map[Void]Void {
+	matchMap := make(map[Void]Void)
+	if re == nil {
+		return matchMap
+	}
+
+	names := re.SubexpNames()
+	for i, name := range names {
+		matchMap[float64(i)] = ""
+		if len(name) > 0 {
+			matchMap[name] = ""
+		}
+	}
+
+	lines := append(append([]string{}, outputLines...), promptStr)
+	if multiLineRe.MatchString(re.String()) {
+		lines = []string{strings.Join(lines, "\n")}
+	}
+
+	for _, line := range lines {
+		submatch := re.FindStringSubmatch(line)
+		if submatch == nil {
+			continue
+		}
+
+		for i, value := range submatch {
+			matchMap[float64(i)] = value
+			if len(names[i]) > 0 {
+				matchMap[names[i]] = value
+			}
+		}
+		break
+	}
+
+	return matchMap
+}
+
+/* expect 결과(output, prompt, exit code) context 에 저장, log 기록
+ */
+func setExpectOutput(context *ReplayerContext, sessionnode *SessionNode, sessionName string,
+	promptStr string, outputLines []string) *errors.Error {
+
 	/* last output 메시지 저장
 	 */
 	context.LastOutput = outputLines
 	context.ExitCode = -1
 	context.LastPromptStr = promptStr /* 마지막 prompt string 저장 */
-	context.LastOutputSessionName = self.SessionName
+	context.LastOutputSessionName = sessionName
 
 	/* output_string, exit_code 값 갱신
 	 */
-	err = context.SetOutputStringVariable()
+	err := context.SetOutputStringVariable()
 	if err != nil {
-		return nil, err.AddMsg(self.ToString())
+		return err
 	}
 
 	/* log 기록
@@ -104,7 +183,7 @@ func (self *Expect) Do(context *ReplayerContext) (Void, *errors.Error) {
 
 			err := sessionnode.Logger.Write(line)
 			if err != nil {
-				return nil, err.AddMsg(self.ToString())
+				return err
 			}
 		}
 	}
@@ -112,15 +191,15 @@ func (self *Expect) Do(context *ReplayerContext) (Void, *errors.Error) {
 	/* expect string match 되고, expect string이 bash이면 exit 코드 확인
 	 * XXX: prompt가 "# "로 끝나면 수행
 	 */
-	isbash, err3 := context.IsBash(self.SessionName)
+	isbash, err3 := context.IsBash(sessionName)
 	if err3 != nil {
-		return nil, err3.AddMsg(self.ToString())
+		return err3
 	}
 
 	if isbash {
-		outputLines1, err1 := ExecRemoteCommand("echo $?", proc, constdef.MAX_OUTPUT_LINE_COUNT, context.LastPromptStr, true)
+		outputLines1, err1 := ExecRemoteCommand("echo $?", sessionnode.Proc, constdef.MAX_OUTPUT_LINE_COUNT, context.LastPromptStr, true)
 		if err1 != nil {
-			return nil, err1.AddMsg(self.ToString())
+			return err1
 		}
 
 		if len(outputLines1) > 0 {
@@ -132,47 +211,99 @@ func (self *Expect) Do(context *ReplayerContext) (Void, *errors.Error) {
 				 */
 				err := context.SetOutputStringVariable()
 				if err != nil {
-					return nil, err.AddMsg(self.ToString())
+					return err
 				}
 			} else {
-				fmt.Println(outputLines1)
-				e := errors.New(fmt.Sprintf("%s", goerr2)).AddMsg(self.ToString())
-				fmt.Println("ERR:", e.ToString(constdef.DEBUG))
+				context.GetPrinter().Println(outputLines1)
+				e := errors.New(fmt.Sprintf("%s", goerr2)).AddMsg(fmt.Sprintf("%s %s", ExpectRcmdStr, sessionName))
+				context.GetPrinter().Println("ERR:", e.ToString(constdef.DEBUG))
 			}
 		}
 	}
 
-	return nil, nil
+	return nil
 }
 
+/* (?m), (?s) flag 로 시작하는 regexp 는 마지막 line 뿐 아니라 여러 line 과 비교
+ */
+var multiLineRe = regexp.MustCompile(`^\(\?[a-zU]*[ms][a-zU]*\)`)
+
 func DoExpect(process *proc.PtyProcess, expectTimeout float64, expectReFlag bool, expectStr string,
-	outputPrintFlag bool, maxOutputLines uint32, lastPromptStr string, lastPromptFlag bool) (bool, string, []string, *errors.Error) {
+	outputPrintFlag bool, printer *fmt.Printer, maxOutputLines uint32, lastPromptStr string, lastPromptFlag bool) (bool, string, []string, *errors.Error) {
+
+	patterns := []*ExpectPattern{&ExpectPattern{ReFlag: expectReFlag, Str: expectStr}}
 
-	if process == nil {
-		return false, "", []string{}, errors.New("Invalid arguments")
+	index, promptStr, outputLines, err := DoExpectMulti(process, expectTimeout, patterns, outputPrintFlag,
+		printer, maxOutputLines, lastPromptStr, lastPromptFlag)
+	if err != nil {
+		return false, "", []string{}, err
+	}
+
+	if index < 0 {
+		return false, "", []string{}, errors.New("timeout")
+	}
+
+	return true, promptStr, outputLines, nil
+}
+
+/* expect pattern
+ */
+type ExpectPattern struct {
+	ReFlag bool
+	Str    string
+}
+
+/* string pattern 은 전체 일치 regexp 로 변환
+ */
+func (self *ExpectPattern) Regexp() (*regexp.Regexp, *errors.Error) {
+	reStr := self.Str
+	if !self.ReFlag {
+		reStr = "^" + regexp.QuoteMeta(self.Str) + "$"
+	}
+
+	re, goerr := regexp.Compile(reStr)
+	if goerr != nil {
+		return nil, errors.New(fmt.Sprintf("%s", goerr))
+	}
+
+	return re, nil
+}
+
+/* patterns 중 처음 일치한 pattern 의 index 를 return
+ * timeout 이면 index -1 과 그때까지 받은 output lines 를 return
+ * outputPrintFlag 이면 output 을 printer 로 출력
+ */
+func DoExpectMulti(process *proc.PtyProcess, expectTimeout float64, patterns []*ExpectPattern,
+	outputPrintFlag bool, printer *fmt.Printer, maxOutputLines uint32, lastPromptStr string, lastPromptFlag bool) (int, string, []string, *errors.Error) {
+
+	if process == nil || len(patterns) == 0 {
+		return -1, "", []string{}, errors.New("Invalid arguments")
 	}
 
 	outputLines := []string{}
 	matchtable := []*proc.LineMatch{}
 
-	if expectReFlag {
-		re1, regexpErr := regexp.Compile(expectStr)
-		if regexpErr != nil {
-			return false, "", []string{}, errors.New(fmt.Sprintf("%s", regexpErr))
+	for _, pattern := range patterns {
+		if pattern.ReFlag {
+			re1, regexpErr := regexp.Compile(pattern.Str)
+			if regexpErr != nil {
+				return -1, "", []string{}, errors.New(fmt.Sprintf("%s", regexpErr))
+			}
+			matchtable = append(matchtable, &proc.LineMatch{
+				LineType:  proc.LINE_TYPE_PROMPT,
+				MatchType: proc.MATCH_TYPE_RE,
+				Re:        re1,
+				Str:       "",
+				MultiLine: multiLineRe.MatchString(pattern.Str),
+			})
+		} else {
+			matchtable = append(matchtable, &proc.LineMatch{
+				LineType:  proc.LINE_TYPE_PROMPT,
+				MatchType: proc.MATCH_TYPE_STR_EXACT,
+				Re:        nil,
+				Str:       pattern.Str,
+			})
 		}
-		matchtable = append(matchtable, &proc.LineMatch{
-			LineType:  proc.LINE_TYPE_PROMPT,
-			MatchType: proc.MATCH_TYPE_RE,
-			Re:        re1,
-			Str:       "",
-		})
-	} else {
-		matchtable = append(matchtable, &proc.LineMatch{
-			LineType:  proc.LINE_TYPE_PROMPT,
-			MatchType: proc.MATCH_TYPE_STR_EXACT,
-			Re:        nil,
-			Str:       expectStr,
-		})
 	}
 
 	sshAuthRe := regexp.MustCompile(`^.*\s+continue connecting \(yes/no.*\)\?\s*`)
@@ -192,46 +323,60 @@ func DoExpect(process *proc.PtyProcess, expectTimeout float64, expectReFlag bool
 
 	timeout := expectTimeout * 1000.0
 	for {
-		rawMsg, lineType, err := process.Read(matchtable, time.Duration(timeout))
+		result, err := process.Expect(matchtable, time.Duration(timeout))
 		if err != nil {
-			return false, "", []string{}, err
+			return -1, "", []string{}, err
+		}
+
+		/* match 이전 output lines 저장
+		 */
+		for _, rawMsg := range result.Lines {
+			if outputPrintFlag {
+				printer.Printf("%s\n", rawMsg)
+			}
+
+			if maxOutputLines > 0 && (uint32(len(outputLines)) >= maxOutputLines) {
+				outputLines = outputLines[1:]
+			}
+			msg := convCharEncoding(rawMsg, process.CharacterSet)
+			outputLines = append(outputLines, msg)
 		}
 
 		if outputPrintFlag {
-			fmt.Printf("%s", rawMsg)
+			printer.Printf("%s", result.Match)
 		}
 
-		switch lineType {
+		switch result.LineType {
 		case proc.LINE_TYPE_SSHAUTH:
 			err1 := process.Write("yes" + process.Eol)
 			if err1 != nil {
-				return false, "", []string{}, err1
+				return -1, "", []string{}, err1
 			}
 			continue
 		case proc.LINE_TYPE_MORE:
 			err1 := process.Write(" ")
 			if err1 != nil {
-				return false, "", []string{}, err1
+				return -1, "", []string{}, err1
 			}
 			continue
-		case proc.LINE_TYPE_OUTPUT_LINE:
-			if maxOutputLines > 0 && (uint32(len(outputLines)) >= maxOutputLines) {
-				outputLines = outputLines[1:]
-			}
-			msg := convCharEncoding(rawMsg, process.CharacterSet)
-			outputLines = append(outputLines, msg)
 		case proc.LINE_TYPE_PROMPT:
-			msg := convCharEncoding(rawMsg, process.CharacterSet)
+			msg := convCharEncoding(result.Match, process.CharacterSet)
+			ll := outputLines
+			// prompt echo line 제거
+			if len(outputLines) > 0 {
+				ll = outputLines[1:]
+			}
+			return result.Index, msg, ll, nil
+		case proc.LINE_TYPE_TIMEOUT:
 			ll := outputLines
 			// prompt echo line 제거
 			if len(outputLines) > 0 {
 				ll = outputLines[1:]
 			}
-			return true, msg, ll, nil
+			return -1, "", ll, nil
 		default:
-			return false, "", []string{}, errors.New("invalid line type")
+			return -1, "", []string{}, errors.New("invalid line type")
 		}
-
 	}
 }
 
@@ -275,7 +420,7 @@ func ExecRemoteCommand(cmd string, proc *proc.PtyProcess, maxOutputLines uint32,
 	}
 	time.Sleep(time.Millisecond * constdef.SEND_INTERVAL_MILLISECOND)
 
-	_, _, outputLines, err := DoExpect(proc, 60.0, true, constdef.BASH_PROMPT2_RE_STR, false,
+	_, _, outputLines, err := DoExpect(proc, 60.0, true, constdef.BASH_PROMPT2_RE_STR, false, nil,
 		maxOutputLines, lastPromptStr, lastPromptFlag)
 
 	if err != nil {
@@ -303,3 +448,331 @@ func RemoteCommand(cmd string, proc *proc.PtyProcess) ([]string, int, *errors.Er
 
 	return outputs, int(exitCode), nil
 }
+
+/* multi pattern expect
+ *  expect 10 S1
+ *  case "Reboot? [y/n]"
+ *      send "y" S1
+ *  case r"[Pp]assword:\s*$"
+ *      send "secret" S1
+ *  timeout
+ *      error "no prompt"
+ *  endexpect
+ */
+type ExpectCase struct {
+	CaseKeyword  string    `@"case"`
+	ExpectReFlag bool      `[ @"r" ]`
+	ExpectStr    string    `@STRING`
+	RcmdList     *RcmdList `[ @@ ]`
+
+	RcmdObjList []RcmdInterface
+}
+
+func (self *ExpectCase) ToString() string {
+	reflag := ""
+	if self.ExpectReFlag {
+		reflag = "r"
+	}
+
+	return fmt.Sprintf("%s %s%s", self.CaseKeyword, reflag, self.ExpectStr)
+}
+
+func (self *ExpectCase) Prepare(context *ReplayerContext) *errors.Error {
+	rcmdobjlist, err := ConvRcmdList2Obj(self.RcmdList)
+	if err != nil {
+		return err
+	}
+
+	self.RcmdObjList = rcmdobjlist
+	return nil
+}
+
+type ExpectTimeoutCase struct {
+	TimeoutKeyword string    `@"timeout"`
+	RcmdList       *RcmdList `[ @@ ]`
+
+	RcmdObjList []RcmdInterface
+}
+
+func (self *ExpectTimeoutCase) ToString() string {
+	return self.TimeoutKeyword
+}
+
+func (self *ExpectTimeoutCase) Prepare(context *ReplayerContext) *errors.Error {
+	rcmdobjlist, err := ConvRcmdList2Obj(self.RcmdList)
+	if err != nil {
+		return err
+	}
+
+	self.RcmdObjList = rcmdobjlist
+	return nil
+}
+
+type ExpectBlock struct {
+	Pos lexer.Position
+
+	Name             string             `@"expect"`
+	ExpectTimeout    float64            `@NUMBER`
+	SessionName      string             `@IDENT`
+	Cases            []*ExpectCase      `@@ { @@ }`
+	TimeoutCase      *ExpectTimeoutCase `[ @@ ]`
+	EndexpectKeyword string             `@"endexpect"`
+}
+
+func NewExpectBlock(text string) (*ExpectBlock, *errors.Error) {
+	target, err := NewStruct(text, &ExpectBlock{})
+	if err != nil {
+		return nil, err
+	}
+	return target.(*ExpectBlock), nil
+}
+
+func (self *ExpectBlock) ToString() string {
+	text := fmt.Sprintf("%s %.1f %s", self.Name, self.ExpectTimeout, self.SessionName)
+	for _, expectcase := range self.Cases {
+		text += " " + expectcase.ToString()
+	}
+
+	if self.TimeoutCase != nil {
+		text += " " + self.TimeoutCase.ToString()
+	}
+
+	return text
+}
+
+func (self *ExpectBlock) Prepare(context *ReplayerContext) *errors.Error {
+	for _, expectcase := range self.Cases {
+		err := expectcase.Prepare(context)
+		if err != nil {
+			return err
+		}
+	}
+
+	if self.TimeoutCase != nil {
+		err := self.TimeoutCase.Prepare(context)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (self *ExpectBlock) Do(context *ReplayerContext) (Void, *errors.Error) {
+	if context == nil {
+		return nil, errors.New("Invalid arguments").AddMsg(self.ToString())
+	}
+
+	sessionnode, err := context.GetSessionNode(self.SessionName)
+	if err != nil {
+		return nil, err.AddMsg(self.ToString())
+	}
+
+	patterns := []*ExpectPattern{}
+	for _, expectcase := range self.Cases {
+		expectStr, err := context.ReplaceVariable(utils.Unquote(expectcase.ExpectStr))
+		if err != nil {
+			return nil, err.AddMsg(self.ToString())
+		}
+		patterns = append(patterns, &ExpectPattern{ReFlag: expectcase.ExpectReFlag, Str: expectStr})
+	}
+
+	index, promptStr, outputLines, err := DoExpectMulti(sessionnode.Proc, self.ExpectTimeout, patterns,
+		context.OutputPrintFlag, context.GetPrinter(), constdef.MAX_OUTPUT_LINE_COUNT, context.LastPromptStr, true)
+	if err != nil {
+		return nil, err.AddMsg(self.ToString())
+	}
+
+	/* timeout, timeout case 가 없으면 error
+	 */
+	if index < 0 {
+		if self.TimeoutCase == nil {
+			return nil, errors.New("timeout").AddMsg(self.ToString())
+		}
+
+		context.LastOutput = outputLines
+		context.ExitCode = -1
+		context.LastOutputSessionName = self.SessionName
+
+		err := context.SetOutputStringVariable()
+		if err != nil {
+			return nil, err.AddMsg(self.ToString())
+		}
+
+		err = self.setMatchVariable(context, index, "")
+		if err != nil {
+			return nil, err.AddMsg(self.ToString())
+		}
+
+		err = setExpectMatchVariable(context, nil, "", outputLines)
+		if err != nil {
+			return nil, err.AddMsg(self.ToString())
+		}
+
+		controlflow, err := playIfRcmdList(self.TimeoutCase.RcmdObjList, context)
+		if err != nil {
+			return controlflow, err.AddMsg(self.TimeoutCase.ToString()).AddMsg(self.ToString())
+		}
+		return controlflow, nil
+	}
+
+	err = setExpectOutput(context, sessionnode, self.SessionName, promptStr, outputLines)
+	if err != nil {
+		return nil, err.AddMsg(self.ToString())
+	}
+
+	err = self.setMatchVariable(context, index, promptStr)
+	if err != nil {
+		return nil, err.AddMsg(self.ToString())
+	}
+
+	err = setExpectMatchVariable(context, patterns[index], promptStr, outputLines)
+	if err != nil {
+		return nil, err.AddMsg(self.ToString())
+	}
+
+	expectcase := self.Cases[index]
+	controlflow, err := playIfRcmdList(expectcase.RcmdObjList, context)
+	if err != nil {
+		return controlflow, err.AddMsg(expectcase.ToString()).AddMsg(self.ToString())
+	}
+
+	return controlflow, nil
+}
+
+/* 일치된 case index, 일치된 string 변수 저장
+ * timeout 이면 index -1
+ */
+func (self *ExpectBlock) setMatchVariable(context *ReplayerContext, index int, matchStr string) *errors.Error {
+	err := context.SetVariable(constdef.EXPECT_INDEX_VARIABLE_NAME, index, "")
+	if err != nil {
+		return err
+	}
+
+	return context.SetVariable(constdef.EXPECT_STRING_VARIABLE_NAME, matchStr, "")
+}
+
+func (self *ExpectBlock) GetName() string {
+	return self.Name
+}
+
+func (self *ExpectBlock) Dump() {
+	repr.Println(self)
+}
+
+/* screen expect, full screen program 의 화면에서 text 대기
+ *  expect screen "Next" 10 S1
+ *  expect screen r"\[ *OK *\]" at 3 10 10 S1   ; row 3, col 10 위치 (0 부터 시작)
+ */
+type ExpectScreen struct {
+	Pos lexer.Position
+
+	Name          string   `@"expect"`
+	ScreenKeyword string   `@"screen"`
+	ExpectReFlag  bool     `[ @"r" ]`
+	ExpectStr     string   `@STRING`
+	Row           *float64 `[ "at" @NUMBER`
+	Col           *float64 `  @NUMBER ]`
+	ExpectTimeout float64  `@NUMBER`
+	SessionName   string   `@IDENT`
+}
+
+func NewExpectScreen(text string) (*ExpectScreen, *errors.Error) {
+	target, err := NewStruct(text, &ExpectScreen{})
+	if err != nil {
+		return nil, err
+	}
+	return target.(*ExpectScreen), nil
+}
+
+func (self *ExpectScreen) ToString() string {
+	reflag := ""
+	if self.ExpectReFlag {
+		reflag = "r"
+	}
+
+	position := ""
+	if self.Row != nil && self.Col != nil {
+		position = fmt.Sprintf(" at %d %d", int(*self.Row), int(*self.Col))
+	}
+
+	return fmt.Sprintf("%s %s %s%s%s %.1f %s", self.Name, self.ScreenKeyword, reflag, self.ExpectStr,
+		position, self.ExpectTimeout, self.SessionName)
+}
+
+func (self *ExpectScreen) Prepare(context *ReplayerContext) *errors.Error {
+	return nil
+}
+
+func (self *ExpectScreen) Do(context *ReplayerContext) (Void, *errors.Error) {
+	if context == nil {
+		return nil, errors.New("Invalid arguments").AddMsg(self.ToString())
+	}
+
+	sessionnode, err := context.GetSessionNode(self.SessionName)
+	if err != nil {
+		return nil, err.AddMsg(self.ToString())
+	}
+
+	expectStr, err := context.ReplaceVariable(utils.Unquote(self.ExpectStr))
+	if err != nil {
+		return nil, err.AddMsg(self.ToString())
+	}
+
+	pattern := &ExpectPattern{ReFlag: self.ExpectReFlag, Str: expectStr}
+	match := &proc.ScreenMatch{
+		MatchType: proc.MATCH_TYPE_STR_CONTAIN,
+		Str:       expectStr,
+		Row:       -1,
+		Col:       0,
+	}
+
+	if self.ExpectReFlag {
+		re, err := pattern.Regexp()
+		if err != nil {
+			return nil, err.AddMsg(self.ToString())
+		}
+		match.MatchType = proc.MATCH_TYPE_RE
+		match.Re = re
+	}
+
+	if self.Row != nil && self.Col != nil {
+		match.Row = int(*self.Row)
+		match.Col = int(*self.Col)
+	}
+
+	matched, text, lines, err := sessionnode.Proc.ExpectScreen(match, time.Duration(self.ExpectTimeout*1000.0))
+	if err != nil {
+		return nil, err.AddMsg(self.ToString())
+	}
+
+	if !matched {
+		return nil, errors.New("timeout").AddMsg(self.ToString())
+	}
+
+	/* screen lines 를 output_string 으로 저장
+	 */
+	context.LastOutput = lines
+	context.ExitCode = -1
+	context.LastOutputSessionName = self.SessionName
+
+	err = context.SetOutputStringVariable()
+	if err != nil {
+		return nil, err.AddMsg(self.ToString())
+	}
+
+	err = setExpectMatchVariable(context, pattern, text, nil)
+	if err != nil {
+		return nil, err.AddMsg(self.ToString())
+	}
+
+	return nil, nil
+}
+
+func (self *ExpectScreen) GetName() string {
+	return self.Name
+}
+
+func (self *ExpectScreen) Dump() {
+	repr.Println(self)
+}
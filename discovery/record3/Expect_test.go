@@ -0,0 +1,43 @@
+package record3
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestGetExpectMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		prompt  string
+		output  []string
+		want    map[Void]Void
+	}{
+		/* 처음 일치하는 line 하나의 group
+		 */
+		{`(\w+) is (up|down)`, "# ", []string{"eth0 is up", "eth1 is down"},
+			map[Void]Void{0.0: "eth0 is up", 1.0: "eth0", 2.0: "up"}},
+		{`(?P<host>\w+)#`, "sw1# ", []string{"show ver"},
+			map[Void]Void{0.0: "sw1#", 1.0: "sw1", "host": "sw1"}},
+		{`(\d+) (x)?`, "# ", []string{"10 "},
+			map[Void]Void{0.0: "10 ", 1.0: "10", 2.0: ""}},
+		/* multi line pattern 은 합친 text 와 비교
+		 */
+		{`(?s)foo(\d+).*bar(\d+)`, "# ", []string{"foo1", "bar2"},
+			map[Void]Void{0.0: "foo1\nbar2", 1.0: "1", 2.0: "2"}},
+		{`(?m)^bar(\d+)$`, "# ", []string{"foo1", "bar2"},
+			map[Void]Void{0.0: "bar2", 1.0: "2"}},
+	}
+
+	for _, test := range tests {
+		got := GetExpectMatch(regexp.MustCompile(test.pattern), test.prompt, test.output)
+		if len(got) != len(test.want) {
+			t.Errorf("%s: got %v, want %v", test.pattern, got, test.want)
+			continue
+		}
+		for key, value := range test.want {
+			if got[key] != value {
+				t.Errorf("%s: [%v] = %q, want %q", test.pattern, key, got[key], value)
+			}
+		}
+	}
+}
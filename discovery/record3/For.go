@@ -3,6 +3,7 @@ package record3
 import (
 	"discovery/errors"
 	"fmt"
+	"github.com/alecthomas/participle/lexer"
 	"github.com/alecthomas/repr"
 	"reflect"
 )
@@ -296,6 +297,8 @@ func (self *ForRangeCondition) Do(rcmdobjlist []RcmdInterface, context *Replayer
 }
 
 type For struct {
+	Pos lexer.Position
+
 	Name              string             `@"for"`
 	ForInCondition    *ForInCondition    `(@@`
 	ForRangeCondition *ForRangeCondition `|@@)`
@@ -7,6 +7,7 @@ import (
 	"discovery/proc"
 	"discovery/utils"
 	"encoding/base64"
+	"github.com/alecthomas/participle/lexer"
 	"github.com/alecthomas/repr"
 	"os"
 	"path"
@@ -16,6 +17,8 @@ import (
 const GetRcmdStr = "get"
 
 type Get struct {
+	Pos lexer.Position
+
 	Name        string  `@"get"`
 	FileName    string  `@STRING`
 	LocalPath   *string `[ @STRING ]`
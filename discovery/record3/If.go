@@ -2,6 +2,7 @@ package record3
 
 import (
 	"discovery/errors"
+	"github.com/alecthomas/participle/lexer"
 	"github.com/alecthomas/repr"
 	"regexp"
 )
@@ -97,6 +98,8 @@ func (self *Else) Do(context *ReplayerContext) (Void, *errors.Error) {
 }
 
 type If struct {
+	Pos lexer.Position
+
 	Name         string      `@"if"`
 	Expr         *Expression `@@`
 	RcmdList     *RcmdList   `[ @@ ]`
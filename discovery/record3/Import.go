@@ -0,0 +1,89 @@
+package record3
+
+import (
+	"discovery/errors"
+	"discovery/fmt"
+	"discovery/utils"
+	"github.com/alecthomas/participle/lexer"
+	"github.com/alecthomas/repr"
+)
+
+const ImportRcmdStr = "import"
+
+/* library record 의 proc 을 가져옴
+ * library record 는 proc, comment, version 만 정의 가능
+ */
+type Import struct {
+	Pos lexer.Position
+
+	Name      string `@"import"`
+	ImportRid string `@STRING`
+}
+
+func NewImport(text string) (*Import, *errors.Error) {
+	target, err := NewStruct(text, &Import{})
+	if err != nil {
+		return nil, err
+	}
+	return target.(*Import), nil
+}
+
+func (self *Import) ToString() string {
+	return fmt.Sprintf("%s %s", self.Name, self.ImportRid)
+}
+
+func (self *Import) Prepare(context *ReplayerContext) *errors.Error {
+	return nil
+}
+
+func (self *Import) Do(context *ReplayerContext) (Void, *errors.Error) {
+	if context == nil {
+		return nil, errors.New("Invalid arguments").AddMsg(self.ToString())
+	}
+
+	importrid, err := context.ReplaceVariable(utils.Unquote(self.ImportRid))
+	if err != nil {
+		return nil, err.AddMsg(self.ToString())
+	}
+
+	name, cate, err := utils.ParseRid(importrid)
+	if err != nil {
+		return nil, err.AddMsg(self.ToString())
+	}
+
+	library, err := NewRecord(name, cate)
+	if err != nil {
+		return nil, err.AddMsg(self.ToString())
+	}
+
+	for _, rcmdObj := range library.RcmdObjList {
+		switch rcmdObj.(type) {
+		case *Proc:
+			proc := rcmdObj.(*Proc)
+
+			err = proc.Prepare(context)
+			if err != nil {
+				return nil, err.AddMsg(proc.ToString()).AddMsg(self.ToString())
+			}
+
+			err = context.SetProc(proc)
+			if err != nil {
+				return nil, err.AddMsg(self.ToString())
+			}
+		case *Comment, *Version:
+		default:
+			return nil, errors.New(fmt.Sprintf("'%s' is not allowed in library, only proc can be defined",
+				rcmdObj.GetName())).AddMsg(self.ToString())
+		}
+	}
+
+	return nil, nil
+}
+
+func (self *Import) GetName() string {
+	return self.Name
+}
+
+func (self *Import) Dump() {
+	repr.Println(self)
+}
@@ -0,0 +1,161 @@
+package record3
+
+import (
+	"discovery/config"
+	"discovery/errors"
+	"discovery/fmt"
+	"discovery/utils"
+	"github.com/alecthomas/participle/lexer"
+	"github.com/alecthomas/repr"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+const IncludeRcmdStr = "include"
+
+/* record fragment include
+ * NewRecord 에서 parsing 시점에 include 된 파일의 rcmd 로 치환되므로 Do 는 수행되지 않음
+ */
+type Include struct {
+	Pos lexer.Position
+
+	Name        string `@"include"`
+	IncludePath string `@STRING`
+}
+
+func NewInclude(text string) (*Include, *errors.Error) {
+	target, err := NewStruct(text, &Include{})
+	if err != nil {
+		return nil, err
+	}
+	return target.(*Include), nil
+}
+
+func (self *Include) ToString() string {
+	return fmt.Sprintf("%s %s", self.Name, self.IncludePath)
+}
+
+func (self *Include) Prepare(context *ReplayerContext) *errors.Error {
+	return nil
+}
+
+func (self *Include) Do(context *ReplayerContext) (Void, *errors.Error) {
+	return nil, errors.New("include can be used only in record file").AddMsg(self.ToString())
+}
+
+func (self *Include) GetName() string {
+	return self.Name
+}
+
+func (self *Include) Dump() {
+	repr.Println(self)
+}
+
+/* include 파일을 parsing 하여 rcmd list 로 return
+ * includeStack 은 include 중인 파일 path, 마지막이 현재 파일
+ */
+func (self *Include) load(category []string, includeStack []string) ([]*Rcmd, *errors.Error) {
+	location := GetPositionString(self.Pos)
+
+	path, err := config.GetLoadPath(utils.Unquote(self.IncludePath), category)
+	if err != nil {
+		return nil, err.AddMsg(self.ToString()).SetPosition(location)
+	}
+	path = filepath.Clean(path)
+
+	for _, includedPath := range includeStack {
+		if includedPath == path {
+			return nil, errors.New(fmt.Sprintf("include cycle, %s -> %s",
+				strings.Join(includeStack, " -> "), path)).AddMsg(self.ToString()).SetPosition(location)
+		}
+	}
+
+	data, oserr := ioutil.ReadFile(path)
+	if oserr != nil {
+		return nil, errors.New(fmt.Sprintf("%s", oserr)).AddMsg(self.ToString()).SetPosition(location)
+	}
+
+	if len(strings.TrimSpace(string(data))) == 0 {
+		return []*Rcmd{}, nil
+	}
+
+	/* parsing error 는 include 파일의 file:line:column 으로 출력
+	 */
+	rcmdlist, err := NewStructWithFilename(string(data), path, &RcmdList{})
+	if err != nil {
+		return nil, err.SetPosition(location)
+	}
+
+	err = ExpandInclude(rcmdlist.(*RcmdList), category, append(includeStack, path))
+	if err != nil {
+		return nil, err
+	}
+
+	return rcmdlist.(*RcmdList).List, nil
+}
+
+/* rcmd list 의 include 를 include 파일의 rcmd 로 치환
+ * if, for 등 nested rcmd list 의 include 도 치환
+ */
+func ExpandInclude(rcmdlist *RcmdList, category []string, includeStack []string) *errors.Error {
+	if rcmdlist == nil || len(includeStack) == 0 {
+		return errors.New("invalid arguments")
+	}
+
+	list := []*Rcmd{}
+	for _, rcmd := range rcmdlist.List {
+		if rcmd.Include != nil {
+			includeList, err := rcmd.Include.load(category, includeStack)
+			if err != nil {
+				return err
+			}
+			list = append(list, includeList...)
+			continue
+		}
+
+		err := expandNestedInclude(reflect.ValueOf(rcmd), category, includeStack)
+		if err != nil {
+			return err
+		}
+		list = append(list, rcmd)
+	}
+	rcmdlist.List = list
+
+	return nil
+}
+
+func expandNestedInclude(value reflect.Value, category []string, includeStack []string) *errors.Error {
+	switch value.Kind() {
+	case reflect.Ptr:
+		if value.IsNil() {
+			return nil
+		}
+
+		if rcmdlist, ok := value.Interface().(*RcmdList); ok {
+			return ExpandInclude(rcmdlist, category, includeStack)
+		}
+		return expandNestedInclude(value.Elem(), category, includeStack)
+	case reflect.Struct:
+		for idx := 0; idx < value.NumField(); idx++ {
+			if !value.Field(idx).CanInterface() {
+				continue
+			}
+
+			err := expandNestedInclude(value.Field(idx), category, includeStack)
+			if err != nil {
+				return err
+			}
+		}
+	case reflect.Slice:
+		for idx := 0; idx < value.Len(); idx++ {
+			err := expandNestedInclude(value.Index(idx), category, includeStack)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -5,10 +5,15 @@ import (
 	"discovery/errors"
 	"discovery/fmt"
 	"discovery/utils"
+	"encoding/json"
+	"github.com/alecthomas/participle/lexer"
+	"io/ioutil"
+	"regexp"
 	"strings"
 
 	"github.com/alecthomas/repr"
 	"github.com/go-ini/ini"
+	"gopkg.in/yaml.v2"
 )
 
 /* ini type
@@ -137,13 +142,144 @@ func (self *IniType) Dump() {
 	repr.Println(self)
 }
 
+var dataVarNameRe = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+/* json, yaml type
+ * as name 이 있으면 파일 전체를 name 변수로, 없으면 최상위 map 의 key 별로 변수 생성
+ */
+type DataType struct {
+	TypeName string  `@("json"|"yaml")`
+	FilePath string  `@STRING`
+	AsName   *string `[ "as" @IDENT ]`
+}
+
+func (self *DataType) ToString() string {
+	text := fmt.Sprintf("%s %s", self.TypeName, self.FilePath)
+
+	if self.AsName != nil {
+		text += fmt.Sprintf(" as %s", *self.AsName)
+	}
+
+	return text
+}
+
+func (self *DataType) Load(context *ReplayerContext) *errors.Error {
+	if context == nil {
+		return errors.New("invalid arguments")
+	}
+
+	loadfile, err := context.ReplaceVariable(utils.Unquote(self.FilePath))
+	if err != nil {
+		return err
+	}
+
+	path, err := config.GetLoadPath(loadfile, context.RecordCategory)
+	if err != nil {
+		return err
+	}
+
+	data, goerr := ioutil.ReadFile(path)
+	if goerr != nil {
+		return errors.New(fmt.Sprintf("%s", goerr))
+	}
+
+	var value interface{}
+	switch self.TypeName {
+	case "json":
+		goerr = json.Unmarshal(data, &value)
+	case "yaml":
+		goerr = yaml.Unmarshal(data, &value)
+		value = convYamlValue(value)
+	default:
+		return errors.New("invalid load file type, only can json or yaml file.")
+	}
+	if goerr != nil {
+		return errors.New(fmt.Sprintf("%s", goerr))
+	}
+
+	if self.AsName != nil {
+		return context.SetVariable(*self.AsName, value, loadfile)
+	}
+
+	vari, err := NewVariable("_", value, "")
+	if err != nil {
+		return err
+	}
+
+	if !IsMap(vari.Value) {
+		return errors.New("top level value have to be map, or use 'as name'")
+	}
+
+	/* 최상위 key 는 변수 이름이므로 IDENT 형식만 가능
+	 */
+	m := vari.Value.(map[Void]Void)
+	for _, key := range sortedMapKeys(m) {
+		name := valueToString(key)
+		if !dataVarNameRe.MatchString(name) {
+			return errors.New(fmt.Sprintf("'%s' is invalid variable name, top level key have to be variable name, or use 'as name'", name))
+		}
+
+		err := context.SetVariable(name, m[key], loadfile)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (self *DataType) Unload(context *ReplayerContext) *errors.Error {
+	if context == nil {
+		return errors.New("invalid arguments")
+	}
+
+	loadfile, err := context.ReplaceVariable(utils.Unquote(self.FilePath))
+	if err != nil {
+		return err
+	}
+
+	return context.DelVariableWithLoadPath(loadfile)
+}
+
+func (self *DataType) Dump() {
+	repr.Println(self)
+}
+
+/* yaml map key 는 json 과 같이 문자열로 변환
+ * yaml 1.1 의 on, yes, no 등은 bool, 정수 key 는 int 로 decode 되므로 "true", "10" 등의 문자열 key
+ * number 값만 float64 로 변환
+ */
+func convYamlValue(value interface{}) interface{} {
+	switch value.(type) {
+	case map[interface{}]interface{}:
+		res := make(map[Void]Void)
+		for k, v := range value.(map[interface{}]interface{}) {
+			res[valueToString(convYamlValue(k))] = convYamlValue(v)
+		}
+		return res
+	case []interface{}:
+		list := []interface{}{}
+		for _, v := range value.([]interface{}) {
+			list = append(list, convYamlValue(v))
+		}
+		return list
+	case int, int64, uint64, float64:
+		return ConvIntToFloat(value)
+	default:
+		return value
+	}
+}
+
 /* Load 정의
  */
 const LoadRcmdStr = "load"
 
 type Load struct {
-	Name    string   `@"load"`
-	IniType *IniType `@@`
+	Pos lexer.Position
+
+	Name     string    `@"load"`
+	IniType  *IniType  `( @@`
+	DataType *DataType ` | @@ )`
 }
 
 func NewLoad(text string) (*Load, *errors.Error) {
@@ -159,6 +295,8 @@ func (self *Load) ToString() string {
 
 	if self.IniType != nil {
 		str += " " + self.IniType.ToString()
+	} else if self.DataType != nil {
+		str += " " + self.DataType.ToString()
 	}
 
 	return str
@@ -175,6 +313,12 @@ func (self *Load) Do(context *ReplayerContext) (Void, *errors.Error) {
 			return nil, err.AddMsg(self.ToString())
 		}
 		return nil, nil
+	} else if self.DataType != nil {
+		err := self.DataType.Load(context)
+		if err != nil {
+			return nil, err.AddMsg(self.ToString())
+		}
+		return nil, nil
 	} else {
 		return nil, errors.New("invalid load arguments").AddMsg(self.ToString())
 	}
@@ -193,8 +337,11 @@ func (self *Load) Dump() {
 const UnloadRcmdStr = "unload"
 
 type Unload struct {
-	Name    string   `@"unload"`
-	IniType *IniType `@@`
+	Pos lexer.Position
+
+	Name     string    `@"unload"`
+	IniType  *IniType  `( @@`
+	DataType *DataType ` | @@ )`
 }
 
 func NewUnload(text string) (*Unload, *errors.Error) {
@@ -209,6 +356,8 @@ func NewUnload(text string) (*Unload, *errors.Error) {
 func (self *Unload) ToString() string {
 	if self.IniType != nil {
 		return fmt.Sprintf("%s %s", self.Name, self.IniType.ToString())
+	} else if self.DataType != nil {
+		return fmt.Sprintf("%s %s", self.Name, self.DataType.ToString())
 	}
 	return ""
 }
@@ -223,6 +372,11 @@ func (self *Unload) Do(context *ReplayerContext) (Void, *errors.Error) {
 		if err != nil {
 			return nil, err.AddMsg(self.ToString())
 		}
+	} else if self.DataType != nil {
+		err := self.DataType.Unload(context)
+		if err != nil {
+			return nil, err.AddMsg(self.ToString())
+		}
 	} else {
 		return nil, errors.New("invalid unload arguments").AddMsg(self.ToString())
 	}
@@ -4,12 +4,15 @@ import (
 	"discovery/errors"
 	"discovery/fmt"
 	"discovery/utils"
+	"github.com/alecthomas/participle/lexer"
 	"github.com/alecthomas/repr"
 )
 
 const LogRcmdStr = "log"
 
 type Log struct {
+	Pos lexer.Position
+
 	Name        string `@"log"`
 	OnFlag      *bool  `(@"on" | "off")`
 	LogPrefix   string `@STRING`
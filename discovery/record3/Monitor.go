@@ -0,0 +1,144 @@
+package record3
+
+import (
+	"discovery/constdef"
+	"discovery/errors"
+	"discovery/fmt"
+	"github.com/alecthomas/participle/lexer"
+	"github.com/alecthomas/repr"
+)
+
+const MonitorRcmdStr = "monitor"
+
+/* monitor start S1 var, session output 을 background 에서 계속 수집
+ * monitor stop S1, 수집한 line 을 var, var_time 변수와 session log 에 기록
+ */
+type Monitor struct {
+	Pos lexer.Position
+
+	Name        string `@"monitor"`
+	Action      string `@( "start" | "stop" )`
+	SessionName string `@IDENT`
+	VarName     string `[ @IDENT ]`
+}
+
+func NewMonitor(text string) (*Monitor, *errors.Error) {
+	target, err := NewStruct(text, &Monitor{})
+	if err != nil {
+		return nil, err
+	}
+	return target.(*Monitor), nil
+}
+
+func (self *Monitor) ToString() string {
+	text := fmt.Sprintf("%s %s %s", self.Name, self.Action, self.SessionName)
+	if len(self.VarName) > 0 {
+		text += " " + self.VarName
+	}
+	return text
+}
+
+func (self *Monitor) Prepare(context *ReplayerContext) *errors.Error {
+	return nil
+}
+
+func (self *Monitor) Do(context *ReplayerContext) (Void, *errors.Error) {
+	if context == nil {
+		return nil, errors.New("Invalid arguments").AddMsg(self.ToString())
+	}
+
+	sessionnode, err := context.GetSessionNode(self.SessionName)
+	if err != nil {
+		return nil, err.AddMsg(self.ToString())
+	}
+
+	switch self.Action {
+	case "start":
+		err = self.start(sessionnode, context)
+	case "stop":
+		err = self.stop(sessionnode, context)
+	default:
+		err = errors.New(fmt.Sprintf("'%s' is invalid monitor action", self.Action))
+	}
+
+	if err != nil {
+		return nil, err.AddMsg(self.ToString())
+	}
+	return nil, nil
+}
+
+func (self *Monitor) start(sessionnode *SessionNode, context *ReplayerContext) *errors.Error {
+	if len(self.VarName) == 0 {
+		return errors.New("monitor start needs variable name")
+	}
+
+	_, err := sessionnode.Proc.StartMonitor(constdef.MONITOR_MAX_LINE_COUNT)
+	if err != nil {
+		return err
+	}
+	sessionnode.MonitorVarName = self.VarName
+
+	if context.OutputPrintFlag {
+		context.GetPrinter().Printf("\n>>> Monitor %s start\n", self.SessionName)
+	}
+
+	return nil
+}
+
+func (self *Monitor) stop(sessionnode *SessionNode, context *ReplayerContext) *errors.Error {
+	if len(self.VarName) > 0 {
+		return errors.New("monitor stop uses variable name of monitor start")
+	}
+
+	monitor := sessionnode.Proc.Monitor
+	lines, err := sessionnode.Proc.StopMonitor()
+	if err != nil {
+		return err
+	}
+
+	lineList := []Void{}
+	timeList := []Void{}
+	for _, line := range lines {
+		timestamp := line.Time.Format("2006-01-02 15:04:05.000")
+		lineList = append(lineList, line.Line)
+		timeList = append(timeList, timestamp)
+
+		err = sessionnode.Logger.Write(fmt.Sprintf("%10s|%s %s\n", "MONITOR", timestamp, line.Line))
+		if err != nil {
+			return err
+		}
+	}
+
+	if monitor.DroppedCount > 0 {
+		err = sessionnode.Logger.Write(fmt.Sprintf("%10s|%d lines are dropped, max %d lines\n",
+			"MONITOR", monitor.DroppedCount, monitor.MaxLineCount))
+		if err != nil {
+			return err
+		}
+	}
+
+	err = context.SetVariable(sessionnode.MonitorVarName, lineList, "")
+	if err != nil {
+		return err
+	}
+
+	err = context.SetVariable(sessionnode.MonitorVarName+constdef.MONITOR_TIME_VARIABLE_NAME_SUFFIX, timeList, "")
+	if err != nil {
+		return err
+	}
+	sessionnode.MonitorVarName = ""
+
+	if context.OutputPrintFlag {
+		context.GetPrinter().Printf("\n>>> Monitor %s stop, %d lines\n", self.SessionName, len(lines))
+	}
+
+	return nil
+}
+
+func (self *Monitor) GetName() string {
+	return self.Name
+}
+
+func (self *Monitor) Dump() {
+	repr.Println(self)
+}
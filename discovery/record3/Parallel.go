@@ -0,0 +1,315 @@
+package record3
+
+import (
+	"discovery/constdef"
+	"discovery/errors"
+	"discovery/fmt"
+	"github.com/alecthomas/participle/lexer"
+	"github.com/alecthomas/repr"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+const ParallelRcmdStr = "parallel"
+
+/* branch 는 SessionName session 에서 rcmd list 를 수행
+ */
+type ParallelBranch struct {
+	BranchKeyword string    `@"branch"`
+	SessionName   string    `@IDENT`
+	RcmdList      *RcmdList `@@`
+
+	RcmdObjList []RcmdInterface
+}
+
+func (self *ParallelBranch) ToString() string {
+	return fmt.Sprintf("%s %s", self.BranchKeyword, self.SessionName)
+}
+
+func (self *ParallelBranch) Prepare(context *ReplayerContext) *errors.Error {
+	rcmdobjlist, err := ConvRcmdList2Obj(self.RcmdList)
+	if err != nil {
+		return err
+	}
+
+	self.RcmdObjList = rcmdobjlist
+	return nil
+}
+
+/* branch context 에서 rcmd list 수행, 결과는 branch record result 에 기록
+ */
+func (self *ParallelBranch) Do(context *ReplayerContext) (Void, *errors.Error) {
+	printflag, depthindent := context.GetResultOptions()
+	if printflag {
+		context.GetPrinter().Println()
+		context.GetPrinter().Printf("%s %s[p] %s%s", depthindent, constdef.ANSI_YELLOW, self.ToString(), constdef.ANSI_END)
+	}
+
+	controlflow, err := PlayRcmdList(self.RcmdObjList, context)
+	if err == nil {
+		switch controlflow.(type) {
+		case int:
+			switch controlflow.(int) {
+			case CF_BREAK, CF_CONTINUE:
+				err = errors.New("break, continue can not be used in branch")
+			}
+		}
+	}
+
+	if err != nil {
+		err = err.AddMsg(self.ToString())
+
+		errorresult, err1 := NewErrorResult(err, context, printflag, depthindent)
+		if err1 != nil {
+			return nil, err1.AddMsg(self.ToString())
+		}
+		context.RecordResult.SetResult(errorresult)
+		return nil, err
+	}
+
+	context.RecordResult.SetResult(nil)
+	return controlflow, nil
+}
+
+/* branch 들을 동시에 수행하고 모두 끝날 때까지 대기
+ * branch 에서 error 또는 fail step 이 있으면 parallel 은 fail
+ */
+type Parallel struct {
+	Pos lexer.Position
+
+	Name               string            `@"parallel"`
+	BranchList         []*ParallelBranch `{ @@ }`
+	EndparallelKeyword string            `@"endparallel"`
+}
+
+func NewParallel(text string) (*Parallel, *errors.Error) {
+	target, err := NewStruct(text, &Parallel{})
+	if err != nil {
+		return nil, err
+	}
+	return target.(*Parallel), nil
+}
+
+func (self *Parallel) ToString() string {
+	sessions := []string{}
+	for _, branch := range self.BranchList {
+		sessions = append(sessions, branch.SessionName)
+	}
+	return fmt.Sprintf("%s %s", self.Name, strings.Join(sessions, ", "))
+}
+
+func (self *Parallel) Prepare(context *ReplayerContext) *errors.Error {
+	if len(self.BranchList) == 0 {
+		return errors.New("parallel needs at least one branch").AddMsg(self.ToString())
+	}
+
+	sessionMap := make(map[string]bool)
+	for _, branch := range self.BranchList {
+		if sessionMap[branch.SessionName] {
+			return errors.New(fmt.Sprintf("'%s' session is used in several branches", branch.SessionName)).AddMsg(self.ToString())
+		}
+		sessionMap[branch.SessionName] = true
+	}
+
+	for _, branch := range self.BranchList {
+		/* branch 의 rcmd 는 branch 의 session 만 사용 가능
+		 * 다른 branch 또는 branch 가 아닌 session 을 여러 branch 에서 동시에 send, expect 하지 않도록 함
+		 */
+		usedSessions := make(map[string]bool)
+		collectSessionNames(reflect.ValueOf(branch.RcmdList), usedSessions)
+		for name := range usedSessions {
+			if name != branch.SessionName {
+				return errors.New(fmt.Sprintf("'%s' session can not be used in branch %s, only branch session can be used",
+					name, branch.SessionName)).AddMsg(branch.ToString()).AddMsg(self.ToString())
+			}
+		}
+
+		err := branch.Prepare(context)
+		if err != nil {
+			return err.AddMsg(branch.ToString()).AddMsg(self.ToString())
+		}
+	}
+
+	return nil
+}
+
+/* rcmd 문법 구조에서 SessionName field 값을 모두 찾음
+ */
+func collectSessionNames(value reflect.Value, names map[string]bool) {
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !value.IsNil() {
+			collectSessionNames(value.Elem(), names)
+		}
+	case reflect.Slice:
+		for i := 0; i < value.Len(); i++ {
+			collectSessionNames(value.Index(i), names)
+		}
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			field := value.Type().Field(i)
+			if len(field.PkgPath) > 0 {
+				continue
+			}
+
+			if field.Name == "SessionName" && field.Type.Kind() == reflect.String {
+				names[value.Field(i).String()] = true
+				continue
+			}
+			collectSessionNames(value.Field(i), names)
+		}
+	}
+}
+
+func (self *Parallel) Do(context *ReplayerContext) (Void, *errors.Error) {
+	if context == nil {
+		return nil, errors.New("Invalid arguments").AddMsg(self.ToString())
+	}
+
+	printflag, depthindent := context.GetResultOptions()
+
+	/* branch session 은 parallel 전에 connect 되어 있어야 함
+	 */
+	for _, branch := range self.BranchList {
+		if _, ok := context.SessionMap[branch.SessionName]; !ok {
+			return nil, errors.New(fmt.Sprintf("'%s' session is not connected", branch.SessionName)).AddMsg(self.ToString())
+		}
+	}
+
+	branchContexts := []*ReplayerContext{}
+	for _, branch := range self.BranchList {
+		rcdresult, err := NewRecordResult(0, branch.ToString(), printflag, depthindent+"  ")
+		if err != nil {
+			return nil, err.AddMsg(self.ToString())
+		}
+
+		if context.RecordResult.Reporter != nil {
+			rcdresult.SetReporter(context.RecordResult.Reporter)
+		}
+		rcdresult.SetPrinter(fmt.NewBufferPrinter(context.GetPrinter()))
+
+		branchContexts = append(branchContexts, context.Fork(rcdresult, branch.SessionName))
+	}
+
+	controlflows := make([]Void, len(self.BranchList))
+	branchErrors := make([]*errors.Error, len(self.BranchList))
+
+	/* branch 출력은 branch 별로 buffer 에 모아 branch 순서대로 상위 context 출력 buffer 로 flush
+	 */
+	flushChans := make([]chan bool, len(self.BranchList))
+	for idx := range flushChans {
+		flushChans[idx] = make(chan bool)
+	}
+
+	var wg sync.WaitGroup
+	for idx, branch := range self.BranchList {
+		wg.Add(1)
+		go func(idx int, branch *ParallelBranch) {
+			defer wg.Done()
+
+			controlflows[idx], branchErrors[idx] = branch.Do(branchContexts[idx])
+
+			if idx > 0 {
+				<-flushChans[idx-1]
+			}
+			branchContexts[idx].GetPrinter().Flush()
+			close(flushChans[idx])
+		}(idx, branch)
+	}
+	wg.Wait()
+
+	/* join, branch 결과와 session, 변수, defer 를 상위 context 에 반영
+	 * 변수는 모든 branch 의 변경 내용을 구한 뒤 branch 순서대로 반영
+	 */
+	varChanges := []VariableChanges{}
+	for _, branchContext := range branchContexts {
+		varChanges = append(varChanges, context.GetVariableChanges(branchContext))
+	}
+
+	var controlflow Void
+	failedBranches := []string{}
+	for idx, branch := range self.BranchList {
+		branchContext := branchContexts[idx]
+
+		step, err := NewStep(branchContext.RecordResult)
+		if err != nil {
+			return nil, err.AddMsg(self.ToString())
+		}
+		context.RecordResult.AddStep(step)
+
+		/* branch 에서 connect, close 한 branch session 반영
+		 */
+		if session, ok := branchContext.SessionMap[branch.SessionName]; ok {
+			context.SessionMap[branch.SessionName] = session
+		} else {
+			delete(context.SessionMap, branch.SessionName)
+		}
+		context.DeferList = append(context.DeferList, branchContext.DeferList...)
+
+		context.ApplyVariableChanges(varChanges[idx])
+
+		if branchErrors[idx] != nil || hasFailStep(branchContext.RecordResult) {
+			failedBranches = append(failedBranches, branch.SessionName)
+		}
+
+		/* proc 안의 parallel 에서 branch 의 return 값은 상위 context 로 전달
+		 */
+		if controlflows[idx] != nil {
+			controlflow = controlflows[idx]
+			context.ProcReturnFlag = branchContext.ProcReturnFlag
+			context.ProcReturnValue = branchContext.ProcReturnValue
+		}
+	}
+
+	for _, err := range branchErrors {
+		if err != nil {
+			return nil, err.AddMsg(self.ToString())
+		}
+	}
+
+	chkResult, err := NewCheckResult("*", self.ToString(), printflag, depthindent)
+	if err != nil {
+		return nil, err.AddMsg(self.ToString())
+	}
+
+	step, err := NewStep(chkResult)
+	if err != nil {
+		return nil, err.AddMsg(self.ToString())
+	}
+	context.RecordResult.AddStep(step)
+	chkResult.Position = GetPositionString(self.Pos)
+
+	if len(failedBranches) > 0 {
+		chkResult.SetResult(constdef.FAIL)
+		chkResult.SetInfo("", "", "", []string{}, -1,
+			fmt.Sprintf("%s, failed branch: %s", self.ToString(), strings.Join(failedBranches, ", ")))
+
+		if !context.FailedButContinue {
+			return CF_RETURN, nil
+		}
+		return controlflow, nil
+	}
+
+	chkResult.SetResult(constdef.SUCCESS)
+	chkResult.SetInfo("", "", "", []string{}, -1, self.ToString())
+
+	return controlflow, nil
+}
+
+func hasFailStep(rcdresult *RecordResult) bool {
+	for _, reportcase := range rcdresult.GetReportCases(rcdresult.Name, "") {
+		if reportcase.Status == "fail" {
+			return true
+		}
+	}
+	return false
+}
+
+func (self *Parallel) GetName() string {
+	return self.Name
+}
+
+func (self *Parallel) Dump() {
+	repr.Println(self)
+}
@@ -0,0 +1,182 @@
+package record3
+
+import (
+	"discovery/errors"
+	"testing"
+)
+
+func newParallelTestContext(t *testing.T) *ReplayerContext {
+	rcdresult, err := NewRecordResult(0, "parallel_test", false, "")
+	if err != nil {
+		t.Fatal(err.ToString(false))
+	}
+
+	context, err := NewReplayerContext("parallel_test", []string{"test"}, t.TempDir(), false,
+		rcdresult, "", false, []string{})
+	if err != nil {
+		t.Fatal(err.ToString(false))
+	}
+
+	for _, name := range []string{"S1", "S2", "S3"} {
+		context.SessionMap[name] = &SessionNode{SessionName: name}
+	}
+	return context
+}
+
+func doParallel(t *testing.T, context *ReplayerContext, text string) *errors.Error {
+	parallel, err := NewParallel(text)
+	if err != nil {
+		t.Fatal(err.ToString(false))
+	}
+
+	err = parallel.Prepare(context)
+	if err != nil {
+		return err
+	}
+
+	_, err = parallel.Do(context)
+	return err
+}
+
+/* branch 는 상위 변수 복사본을 사용, join 에서 branch 순서대로 변경 내용 반영
+ * go test -race 로 실행
+ */
+func TestParallelBranchVariables(t *testing.T) {
+	context := newParallelTestContext(t)
+
+	if err := context.SetVariable("shared", 5.0, ""); err != nil {
+		t.Fatal(err.ToString(false))
+	}
+	if err := context.SetVariable("list", []Void{1.0, 2.0}, ""); err != nil {
+		t.Fatal(err.ToString(false))
+	}
+	if err := context.SetVariable("old", 1.0, ""); err != nil {
+		t.Fatal(err.ToString(false))
+	}
+
+	err := doParallel(t, context, `parallel
+branch S1
+    set shared 1
+    seta list[0] = 10
+    set count1 3
+    unset old
+branch S2
+    set shared 2
+    seta list[1] = 20
+    set count2 4
+endparallel`)
+	if err != nil {
+		t.Fatal(err.ToString(false))
+	}
+
+	want := map[string]Void{"shared": 2.0, "count1": 3.0, "count2": 4.0}
+	for name, value := range want {
+		got, err := context.GetVariableValue(name)
+		if err != nil {
+			t.Fatal(err.ToString(false))
+		}
+		if got != value {
+			t.Errorf("%s = %v, want %v", name, got, value)
+		}
+	}
+
+	/* 같은 변수를 변경하면 뒤 branch 값
+	 */
+	list, err := context.GetVariableValue("list")
+	if err != nil {
+		t.Fatal(err.ToString(false))
+	}
+	if list.([]Void)[0] != 1.0 || list.([]Void)[1] != 20.0 {
+		t.Errorf("list = %v, want [1 20]", list)
+	}
+
+	if _, err := context.GetVariableValue("old"); err == nil {
+		t.Error("old is unset in branch, want error")
+	}
+}
+
+/* proc 안의 branch 의 return 값은 call 결과로 전달
+ */
+func TestParallelBranchReturn(t *testing.T) {
+	context := newParallelTestContext(t)
+
+	rcmdlist, err := NewStruct(`proc p()
+    parallel
+    branch S1
+        return 5
+    branch S2
+        set q 1
+    endparallel
+    return 7
+endproc
+call p() r
+set after 1`, &RcmdList{})
+	if err != nil {
+		t.Fatal(err.ToString(false))
+	}
+
+	err = RegisterProcList(rcmdlist.(*RcmdList), context)
+	if err != nil {
+		t.Fatal(err.ToString(false))
+	}
+
+	rcmdobjlist, err := ConvRcmdList2Obj(rcmdlist.(*RcmdList))
+	if err != nil {
+		t.Fatal(err.ToString(false))
+	}
+
+	controlflow, err := PlayRcmdList(rcmdobjlist, context)
+	if err != nil {
+		t.Fatal(err.ToString(false))
+	}
+	if controlflow == CF_RETURN {
+		t.Fatal("record is stopped by branch return")
+	}
+
+	want := map[string]Void{"r": 5.0, "after": 1.0}
+	for name, value := range want {
+		got, err := context.GetVariableValue(name)
+		if err != nil {
+			t.Fatal(err.ToString(false))
+		}
+		if got != value {
+			t.Errorf("%s = %v, want %v", name, got, value)
+		}
+	}
+}
+
+func TestParallelBranchSession(t *testing.T) {
+	context := newParallelTestContext(t)
+
+	err := doParallel(t, context, `parallel
+branch S1
+    send "echo" S2
+branch S2
+    set a 1
+endparallel`)
+	if err == nil {
+		t.Error("branch S1 uses S2 session, want error")
+	}
+
+	/* branch 가 아닌 session 도 사용 불가
+	 */
+	err = doParallel(t, context, `parallel
+branch S1
+    send "echo" S3
+branch S2
+    set a 1
+endparallel`)
+	if err == nil {
+		t.Error("branch S1 uses S3 session, want error")
+	}
+
+	err = doParallel(t, context, `parallel
+branch S1
+    set a 1
+branch S4
+    set a 1
+endparallel`)
+	if err == nil {
+		t.Error("S4 session is not connected, want error")
+	}
+}
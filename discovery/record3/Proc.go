@@ -0,0 +1,131 @@
+package record3
+
+import (
+	"discovery/errors"
+	"discovery/fmt"
+	"github.com/alecthomas/participle/lexer"
+	"github.com/alecthomas/repr"
+	"reflect"
+	"strings"
+)
+
+const ProcRcmdStr = "proc"
+
+/* proc 정의, call 로 호출
+ * proc 은 record 재생 전 RegisterProcList 로 context proc map 에 등록
+ * 정의 위치와 관계없이 call 가능, if 등의 block 안의 정의도 수행 여부와 관계없이 등록
+ */
+type Proc struct {
+	Pos lexer.Position
+
+	Name           string    `@"proc"`
+	ProcName       string    `@IDENT`
+	Params         []string  `"(" [ @IDENT { "," @IDENT } ] ")"`
+	RcmdList       *RcmdList `@@`
+	EndprocKeyword string    `@"endproc"`
+
+	RcmdObjList []RcmdInterface
+}
+
+func NewProc(text string) (*Proc, *errors.Error) {
+	target, err := NewStruct(text, &Proc{})
+	if err != nil {
+		return nil, err
+	}
+	return target.(*Proc), nil
+}
+
+func (self *Proc) ToString() string {
+	return fmt.Sprintf("%s %s(%s)", self.Name, self.ProcName, strings.Join(self.Params, ", "))
+}
+
+func (self *Proc) Prepare(context *ReplayerContext) *errors.Error {
+	if self.RcmdObjList != nil {
+		return nil
+	}
+
+	rcmdobjlist, err := ConvRcmdList2Obj(self.RcmdList)
+	if err != nil {
+		return err
+	}
+
+	self.RcmdObjList = rcmdobjlist
+	return nil
+}
+
+func (self *Proc) Do(context *ReplayerContext) (Void, *errors.Error) {
+	if context == nil {
+		return nil, errors.New("Invalid arguments").AddMsg(self.ToString())
+	}
+
+	/* record 재생 전에 등록됨
+	 */
+	return nil, nil
+}
+
+/* record rcmd list 의 모든 proc 정의를 context 에 등록
+ * 같은 이름의 proc 이 여러번 정의되면 에러
+ */
+func RegisterProcList(rcmdlist *RcmdList, context *ReplayerContext) *errors.Error {
+	if context == nil {
+		return errors.New("Invalid arguments")
+	}
+
+	procList := []*Proc{}
+	collectProcList(reflect.ValueOf(rcmdlist), &procList, make(map[*Proc]bool))
+
+	defined := make(map[string]*Proc)
+	for _, proc := range procList {
+		if prev, ok := defined[proc.ProcName]; ok {
+			return errors.New(fmt.Sprintf("'%s' proc is already defined at %s", proc.ProcName,
+				GetPositionString(prev.Pos))).SetPosition(GetPositionString(proc.Pos))
+		}
+		defined[proc.ProcName] = proc
+
+		err := context.SetProc(proc)
+		if err != nil {
+			return err.AddMsg(proc.ToString()).SetPosition(GetPositionString(proc.Pos))
+		}
+	}
+
+	return nil
+}
+
+/* rcmd 구조체를 따라가며 proc 수집, Prepare 후의 RcmdObjList 에서 중복되는 proc 은 제외
+ */
+func collectProcList(value reflect.Value, procList *[]*Proc, seen map[*Proc]bool) {
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if value.IsNil() {
+			return
+		}
+
+		if proc, ok := value.Interface().(*Proc); ok {
+			if seen[proc] {
+				return
+			}
+			seen[proc] = true
+			*procList = append(*procList, proc)
+		}
+		collectProcList(value.Elem(), procList, seen)
+	case reflect.Slice:
+		for i := 0; i < value.Len(); i++ {
+			collectProcList(value.Index(i), procList, seen)
+		}
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			if len(value.Type().Field(i).PkgPath) > 0 {
+				continue
+			}
+			collectProcList(value.Field(i), procList, seen)
+		}
+	}
+}
+
+func (self *Proc) GetName() string {
+	return self.Name
+}
+
+func (self *Proc) Dump() {
+	repr.Println(self)
+}
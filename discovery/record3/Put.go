@@ -7,6 +7,7 @@ import (
 	"discovery/fmt"
 	"discovery/proc"
 	"discovery/utils"
+	"github.com/alecthomas/participle/lexer"
 	"github.com/alecthomas/repr"
 	"os"
 	"path"
@@ -16,6 +17,8 @@ import (
 const PutRcmdStr = "put"
 
 type Put struct {
+	Pos lexer.Position
+
 	Name        string  `@"put"`
 	FileName    string  `@STRING`
 	RemotePath  *string `[ @STRING ]`
@@ -152,7 +155,7 @@ func DoPut(putfilepath string, remotePath string, category []string, proc *proc.
 			return reterror
 		}
 
-		_, _, _, err := DoExpect(proc, 10.0, true, constdef.BASH_PROMPT_RE_STR, false, constdef.MAX_OUTPUT_LINE_COUNT, "", false)
+		_, _, _, err := DoExpect(proc, 10.0, true, constdef.BASH_PROMPT_RE_STR, false, nil, constdef.MAX_OUTPUT_LINE_COUNT, "", false)
 		if err != nil {
 			reterror = err
 			return reterror
@@ -248,7 +251,7 @@ func sendMsgArr(msgArr []string, proc *proc.PtyProcess) *errors.Error {
 			return err
 		}
 
-		_, _, _, err = DoExpect(proc, 60.0, true, constdef.BASH_PROMPT_RE_STR, false, constdef.MAX_OUTPUT_LINE_COUNT, "", false)
+		_, _, _, err = DoExpect(proc, 60.0, true, constdef.BASH_PROMPT_RE_STR, false, nil, constdef.MAX_OUTPUT_LINE_COUNT, "", false)
 		if err != nil {
 			return err
 		}
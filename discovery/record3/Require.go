@@ -5,12 +5,15 @@ import (
 	"discovery/errors"
 	"discovery/fmt"
 	"discovery/utils"
+	"github.com/alecthomas/participle/lexer"
 	"github.com/alecthomas/repr"
 )
 
 const RequireRcmdStr = "require"
 
 type Require struct {
+	Pos lexer.Position
+
 	Name         string `@"require"`
 	RequireRid   string `@STRING`
 	requireCount uint32
@@ -53,7 +56,7 @@ func (self *Require) Do(context *ReplayerContext) (Void, *errors.Error) {
 	tmpRequireRid := utils.Rid(name, cate)
 
 	if tmpCurrentRid == tmpRequireRid {
-		fmt.Println("\n* Skip require, same record")
+		context.GetPrinter().Println("\n* Skip require, same record")
 		return nil, nil
 	}
 
@@ -84,6 +87,11 @@ func (self *Require) Do(context *ReplayerContext) (Void, *errors.Error) {
 		return nil, err.AddMsg(self.ToString())
 	}
 
+	if context.RecordResult.Reporter != nil {
+		rcdresult.SetReporter(context.RecordResult.Reporter)
+	}
+	rcdresult.SetPrinter(context.GetPrinter())
+
 	requireContext, err := NewReplayerContext(name, cate, logdir, context.OutputPrintFlag,
 		rcdresult, context.ForceEnvId, context.NoEnvHashCheck, context.Args)
 
@@ -91,6 +99,11 @@ func (self *Require) Do(context *ReplayerContext) (Void, *errors.Error) {
 		return nil, err.AddMsg(self.ToString())
 	}
 	defer requireContext.Close()
+	requireContext.LockWait = context.LockWait
+	requireContext.ParentEnvLease = context.EnvLease
+	if requireContext.ParentEnvLease == nil {
+		requireContext.ParentEnvLease = context.ParentEnvLease
+	}
 
 	/* VarMapSlice 상속
 	 */
@@ -98,8 +111,8 @@ func (self *Require) Do(context *ReplayerContext) (Void, *errors.Error) {
 	requireContext.PushVarMapSlice(NewVariableMap())
 
 	if printflag {
-		fmt.Println()
-		fmt.Printf("%s %s[r] require \"%s\"%s", depthindent1, constdef.ANSI_YELLOW, requirerid, constdef.ANSI_END)
+		context.GetPrinter().Println()
+		context.GetPrinter().Printf("%s %s[r] require \"%s\"%s", depthindent1, constdef.ANSI_YELLOW, requirerid, constdef.ANSI_END)
 	}
 
 	defer func() {
@@ -0,0 +1,136 @@
+package record3
+
+import (
+	"discovery/constdef"
+	"discovery/errors"
+	"discovery/fmt"
+	"discovery/utils"
+	"encoding/json"
+	"github.com/alecthomas/participle/lexer"
+	"github.com/alecthomas/repr"
+	"strings"
+)
+
+/* rest 정의
+ * rest "node" "command" varname [with {map}]
+ * etc/restapi 의 command json 으로 요청 후 varname_success, varname_status_code, varname_response 변수 설정
+ */
+const RestRcmdStr = "rest"
+
+type Rest struct {
+	Pos lexer.Position
+
+	Name     string      `@"rest"`
+	NodeName string      `@STRING`
+	Command  string      `@STRING`
+	VarName  string      `@IDENT`
+	With     *Expression `[ "with" @@ ]`
+}
+
+func NewRest(text string) (*Rest, *errors.Error) {
+	target, err := NewStruct(text, &Rest{})
+	if err != nil {
+		return nil, err
+	}
+	return target.(*Rest), nil
+}
+
+func (self *Rest) ToString() string {
+	text := fmt.Sprintf("%s %s %s %s", self.Name, self.NodeName, self.Command, self.VarName)
+	if self.With != nil {
+		text += " with " + self.With.ToString()
+	}
+	return text
+}
+
+func (self *Rest) Prepare(context *ReplayerContext) *errors.Error {
+	return nil
+}
+
+func (self *Rest) Do(context *ReplayerContext) (Void, *errors.Error) {
+	if context == nil {
+		return nil, errors.New("Invalid arguments").AddMsg(self.ToString())
+	}
+
+	if context.Env == nil {
+		return nil, errors.New("environment configuration is not loaded").AddMsg(self.ToString())
+	}
+
+	nodename, err := context.ReplaceVariable(utils.Unquote(self.NodeName))
+	if err != nil {
+		return nil, err.AddMsg(self.ToString())
+	}
+
+	command, err := context.ReplaceVariable(utils.Unquote(self.Command))
+	if err != nil {
+		return nil, err.AddMsg(self.ToString())
+	}
+
+	/* {변수} 치환 값
+	 */
+	referenceData := make(map[Void]Void)
+	if self.With != nil {
+		value, err := self.With.Do(context)
+		if err != nil {
+			return nil, err.AddMsg(self.ToString())
+		}
+
+		data, ok := value.(map[Void]Void)
+		if !ok {
+			return nil, errors.New("with value is not a map").AddMsg(self.ToString())
+		}
+		referenceData = data
+	}
+
+	client, err := context.GetRestClient(nodename)
+	if err != nil {
+		return nil, err.AddMsg(self.ToString())
+	}
+
+	success, statusCode, body, err := client.Request(command, referenceData)
+	if err != nil {
+		return nil, err.AddMsg(self.ToString())
+	}
+
+	/* json body 는 map, list 로 변환, json 이 아니면 문자열
+	 */
+	var response Void = string(body)
+	var jsonBody interface{}
+	if goerr := json.Unmarshal(body, &jsonBody); goerr == nil && jsonBody != nil {
+		response = jsonBody
+	}
+
+	err = context.SetVariable(self.VarName+constdef.REST_RESPONSE_IS_SUCCESS_VARIABLE_NAME_SUFFIX, success, "")
+	if err != nil {
+		return nil, err.AddMsg(self.ToString())
+	}
+
+	err = context.SetVariable(self.VarName+constdef.REST_RESPONSE_STATUS_CODE_VARIABLE_NAME_SUFFIX, statusCode, "")
+	if err != nil {
+		return nil, err.AddMsg(self.ToString())
+	}
+
+	err = context.SetVariable(self.VarName+constdef.REST_RESPONSE_BODY_VARIABLE_NAME_SUFFIX, response, "")
+	if err != nil {
+		return nil, err.AddMsg(self.ToString())
+	}
+
+	// rest 결과 LastOutput, output_string 변수에 설정
+	context.LastOutput = strings.Split(strings.TrimRight(string(body), "\r\n"), "\n")
+	context.ExitCode = -1
+
+	err = context.SetOutputStringVariable()
+	if err != nil {
+		return nil, err.AddMsg(self.ToString())
+	}
+
+	return nil, nil
+}
+
+func (self *Rest) GetName() string {
+	return self.Name
+}
+
+func (self *Rest) Dump() {
+	repr.Println(self)
+}
@@ -2,16 +2,25 @@ package record3
 
 import (
 	"discovery/errors"
+	"github.com/alecthomas/participle/lexer"
 	"github.com/alecthomas/repr"
 )
 
 const ReturnRcmdStr = "return"
 
+/* proc 에서는 Value 를 call 의 결과로 넘김
+ */
 type Return struct {
-	Name string `@"return"`
+	Pos lexer.Position
+
+	Name  string      `@"return"`
+	Value *Expression `[ @@ ]`
 }
 
 func (self *Return) ToString() string {
+	if self.Value != nil {
+		return self.Name + " " + self.Value.ToString()
+	}
 	return self.Name
 }
 
@@ -20,6 +29,28 @@ func (self *Return) Prepare(context *ReplayerContext) *errors.Error {
 }
 
 func (self *Return) Do(context *ReplayerContext) (Void, *errors.Error) {
+	if context == nil {
+		return nil, errors.New("Invalid arguments").AddMsg(self.ToString())
+	}
+
+	if context.ProcDepth == 0 {
+		if self.Value != nil {
+			return nil, errors.New("return value can be used only in proc").AddMsg(self.ToString())
+		}
+		return CF_RETURN, nil
+	}
+
+	var value Void
+	if self.Value != nil {
+		res, err := self.Value.Do(context)
+		if err != nil {
+			return nil, err.AddMsg(self.ToString())
+		}
+		value = res
+	}
+
+	context.ProcReturnFlag = true
+	context.ProcReturnValue = value
 	return CF_RETURN, nil
 }
 
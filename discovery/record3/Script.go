@@ -6,6 +6,7 @@ import (
 	"discovery/errors"
 	"discovery/fmt"
 	"discovery/utils"
+	"github.com/alecthomas/participle/lexer"
 	"github.com/alecthomas/repr"
 )
 
@@ -15,6 +16,8 @@ const ScriptRcmdStr = "script"
  * 옵션으로 checker 설정할 경우 checker로 동작, fail 발생 시 중단 된다
  */
 type Script struct {
+	Pos lexer.Position
+
 	Name        string  `@"script"`
 	ScriptName  string  `@STRING`
 	VarName     *string `[ [@IDENT]`        // script output string이 저장됨
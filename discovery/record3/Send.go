@@ -5,6 +5,7 @@ import (
 	"discovery/errors"
 	"discovery/fmt"
 	"discovery/utils"
+	"github.com/alecthomas/participle/lexer"
 	"github.com/alecthomas/repr"
 	"time"
 )
@@ -12,6 +13,8 @@ import (
 const SendRcmdStr = "send"
 
 type Send struct {
+	Pos lexer.Position
+
 	Name        string `@"send"`
 	Command     string `@STRING`
 	SessionName string `@IDENT`
@@ -74,7 +77,7 @@ func (self *Send) Do(context *ReplayerContext) (Void, *errors.Error) {
 	}
 
 	if command == "^C" {
-		command = string(0x03)
+		command = string(rune(0x03))
 	}
 
 	/* Ctrl + c 는 LastSend 에 저장하지 않음
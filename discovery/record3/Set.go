@@ -3,6 +3,7 @@ package record3
 import (
 	"discovery/errors"
 	"discovery/fmt"
+	"github.com/alecthomas/participle/lexer"
 	"github.com/alecthomas/repr"
 	"strings"
 )
@@ -12,6 +13,8 @@ import (
 const SetRcmdStr = "set"
 
 type Set struct {
+	Pos lexer.Position
+
 	Name    string      `@"set"`
 	VarName string      `@IDENT`
 	Expr    *Expression `@@`
@@ -45,8 +48,7 @@ func (self *Set) Do(context *ReplayerContext) (Void, *errors.Error) {
 
 	/* set varname이 function name이랑 같은기 검사
 	 */
-	_, ok := context.FunctionMap[self.VarName]
-	if ok {
+	if IsFunctionToken(self.VarName) {
 		return nil, errors.New(fmt.Sprintf("can't set to '%s' function name as variable name", self.VarName)).AddMsg(self.ToString())
 	}
 
@@ -78,6 +80,8 @@ func (self *Set) Dump() {
 const UnsetRcmdStr = "unset"
 
 type Unset struct {
+	Pos lexer.Position
+
 	Name          string       `@"unset"`
 	PrimValueList []*PrimValue `{ @@ }`
 }
@@ -114,8 +118,7 @@ func (self *Unset) Do(context *ReplayerContext) (Void, *errors.Error) {
 
 		/* check function name
 		 */
-		_, ok := context.FunctionMap[varName]
-		if ok {
+		if IsFunctionToken(varName) {
 			return nil, errors.New(fmt.Sprintf("'%s' is function name", varName)).AddMsg(self.ToString())
 		}
 
@@ -158,6 +161,8 @@ func (self *Unset) Dump() {
 const SetaRcmdStr = "seta"
 
 type Seta struct {
+	Pos lexer.Position
+
 	Name          string      `@"seta"`
 	LeftPrimValue *PrimValue  `@@ "="`
 	RightExpr     *Expression `@@`
@@ -199,8 +204,7 @@ func (self *Seta) Do(context *ReplayerContext) (Void, *errors.Error) {
 
 	/* set varname이 function name이랑 같은기 검사
 	 */
-	_, ok := context.FunctionMap[varName]
-	if ok {
+	if IsFunctionToken(varName) {
 		return nil, errors.New(fmt.Sprintf("can't set to '%s' function name as variable name", varName)).AddMsg(self.ToString())
 	}
 
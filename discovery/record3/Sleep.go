@@ -3,6 +3,7 @@ package record3
 import (
 	"discovery/errors"
 	"discovery/fmt"
+	"github.com/alecthomas/participle/lexer"
 	"github.com/alecthomas/repr"
 	"time"
 )
@@ -10,6 +11,8 @@ import (
 const SleepRcmdStr = "sleep"
 
 type Sleep struct {
+	Pos lexer.Position
+
 	Name             string  `@"sleep"`
 	SleepMilliSecond float64 `@NUMBER`
 }
@@ -40,13 +43,13 @@ func (self *Sleep) Do(context *ReplayerContext) (Void, *errors.Error) {
 	defer func() {
 		close(exitChan)
 		if context.OutputPrintFlag {
-			fmt.Println("")
+			context.GetPrinter().Println("")
 		}
 	}()
 
 	go func() {
 		if context.OutputPrintFlag {
-			fmt.Printf("\n>>> Sleep %.1f Millisecond ", self.SleepMilliSecond)
+			context.GetPrinter().Printf("\n>>> Sleep %.1f Millisecond ", self.SleepMilliSecond)
 		}
 		for {
 			select {
@@ -54,7 +57,7 @@ func (self *Sleep) Do(context *ReplayerContext) (Void, *errors.Error) {
 				return
 			default:
 				if context.OutputPrintFlag {
-					fmt.Printf(".")
+					context.GetPrinter().Printf(".")
 				}
 				time.Sleep(time.Second * 1)
 			}
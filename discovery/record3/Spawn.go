@@ -7,12 +7,15 @@ import (
 	"discovery/fmt"
 	"discovery/proc"
 	"discovery/utils"
+	"github.com/alecthomas/participle/lexer"
 	"github.com/alecthomas/repr"
 )
 
 const SpawnRcmdStr = "spawn"
 
 type Spawn struct {
+	Pos lexer.Position
+
 	Name        string `@"spawn"`
 	Command     string `@STRING`
 	SessionName string `@IDENT`
@@ -7,6 +7,7 @@ import (
 	"discovery/fmt"
 	"discovery/utils"
 	"encoding/csv"
+	"github.com/alecthomas/participle/lexer"
 	"github.com/alecthomas/repr"
 	"io"
 	"os"
@@ -180,6 +181,8 @@ func (self *Row) Do(rcmdObjList []RcmdInterface, context *ReplayerContext) (Void
 }
 
 type Table struct {
+	Pos lexer.Position
+
 	Name            string    `@"table"`
 	Csv             *Csv      `(@@`
 	Row             *Row      `|@@)`
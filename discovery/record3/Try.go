@@ -0,0 +1,182 @@
+package record3
+
+import (
+	"discovery/errors"
+	"github.com/alecthomas/participle/lexer"
+	"github.com/alecthomas/repr"
+)
+
+const TryRcmdStr = "try"
+
+/* catch 변수 map 의 key
+ */
+const (
+	TRY_ERROR_MESSAGE_KEY  = "message"
+	TRY_ERROR_POSITION_KEY = "position"
+)
+
+type TryCatch struct {
+	CatchKeyword string    `@"catch"`
+	VarName      string    `[ @IDENT ]`
+	RcmdList     *RcmdList `@@`
+
+	RcmdObjList []RcmdInterface
+}
+
+func (self *TryCatch) ToString() string {
+	if len(self.VarName) > 0 {
+		return self.CatchKeyword + " " + self.VarName
+	}
+	return self.CatchKeyword
+}
+
+func (self *TryCatch) Prepare(context *ReplayerContext) *errors.Error {
+	rcmdobjlist, err := ConvRcmdList2Obj(self.RcmdList)
+	if err != nil {
+		return err
+	}
+
+	self.RcmdObjList = rcmdobjlist
+	return nil
+}
+
+/* catch 변수는 message, position key 를 갖는 map
+ */
+func (self *TryCatch) Do(tryErr *errors.Error, context *ReplayerContext) (Void, *errors.Error) {
+	varmap := NewVariableMap()
+	context.PushVarMapSlice(varmap)
+	defer context.PopVarMapSlice()
+
+	if len(self.VarName) > 0 {
+		value := map[Void]Void{
+			TRY_ERROR_MESSAGE_KEY:  tryErr.Msg,
+			TRY_ERROR_POSITION_KEY: tryErr.Position,
+		}
+
+		err := context.SetVariable(self.VarName, value, "")
+		if err != nil {
+			return nil, err.AddMsg(self.ToString())
+		}
+	}
+
+	controlflow, err := PlayRcmdList(self.RcmdObjList, context)
+	if err != nil {
+		return nil, err.AddMsg(self.ToString())
+	}
+	return controlflow, nil
+}
+
+type TryFinally struct {
+	FinallyKeyword string    `@"finally"`
+	RcmdList       *RcmdList `@@`
+
+	RcmdObjList []RcmdInterface
+}
+
+func (self *TryFinally) ToString() string {
+	return self.FinallyKeyword
+}
+
+func (self *TryFinally) Prepare(context *ReplayerContext) *errors.Error {
+	rcmdobjlist, err := ConvRcmdList2Obj(self.RcmdList)
+	if err != nil {
+		return err
+	}
+
+	self.RcmdObjList = rcmdobjlist
+	return nil
+}
+
+func (self *TryFinally) Do(context *ReplayerContext) (Void, *errors.Error) {
+	controlflow, err := playIfRcmdList(self.RcmdObjList, context)
+	if err != nil {
+		return nil, err.AddMsg(self.ToString())
+	}
+	return controlflow, nil
+}
+
+/* try 에서 발생한 rcmd error 를 catch 에서 처리하고 계속 진행
+ * finally 는 error, control flow 와 상관없이 항상 수행
+ */
+type Try struct {
+	Pos lexer.Position
+
+	Name          string      `@"try"`
+	RcmdList      *RcmdList   `@@`
+	Catch         *TryCatch   `[ @@ ]`
+	Finally       *TryFinally `[ @@ ]`
+	EndtryKeyword string      `@"endtry"`
+
+	RcmdObjList []RcmdInterface
+}
+
+func NewTry(text string) (*Try, *errors.Error) {
+	target, err := NewStruct(text, &Try{})
+	if err != nil {
+		return nil, err
+	}
+	return target.(*Try), nil
+}
+
+func (self *Try) ToString() string {
+	return self.Name
+}
+
+func (self *Try) Prepare(context *ReplayerContext) *errors.Error {
+	rcmdobjlist, err := ConvRcmdList2Obj(self.RcmdList)
+	if err != nil {
+		return err
+	}
+	self.RcmdObjList = rcmdobjlist
+
+	if self.Catch != nil {
+		err = self.Catch.Prepare(context)
+		if err != nil {
+			return err
+		}
+	}
+
+	if self.Finally != nil {
+		err = self.Finally.Prepare(context)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (self *Try) Do(context *ReplayerContext) (Void, *errors.Error) {
+	if context == nil {
+		return nil, errors.New("Invalid arguments").AddMsg(self.ToString())
+	}
+
+	controlflow, err := playIfRcmdList(self.RcmdObjList, context)
+	if err != nil && self.Catch != nil {
+		controlflow, err = self.Catch.Do(err, context)
+	}
+
+	if self.Finally != nil {
+		finallyControlflow, finallyErr := self.Finally.Do(context)
+		if finallyErr != nil {
+			return nil, finallyErr.AddMsg(self.ToString())
+		}
+
+		if finallyControlflow != nil {
+			controlflow = finallyControlflow
+		}
+	}
+
+	if err != nil {
+		return nil, err.AddMsg(self.ToString())
+	}
+	return controlflow, nil
+}
+
+func (self *Try) GetName() string {
+	return self.Name
+}
+
+func (self *Try) Dump() {
+	repr.Println(self)
+}
@@ -0,0 +1,181 @@
+package record3
+
+import (
+	"discovery/constdef"
+	"discovery/errors"
+	"discovery/fmt"
+	"github.com/alecthomas/participle/lexer"
+	"github.com/alecthomas/repr"
+	"time"
+)
+
+const UntilRcmdStr = "until"
+
+/* condition 이 만족할 때까지 Every(ms) 간격으로 body 를 반복 수행
+ * Timeout(초) 동안 만족하지 않으면 check 와 같이 fail step 으로 기록
+ */
+type Until struct {
+	Pos lexer.Position
+
+	Name            string      `@"until"`
+	Condition       *Expression `@@`
+	Every           float64     `"every" @NUMBER`
+	Timeout         float64     `"timeout" @NUMBER`
+	RcmdList        *RcmdList   `@@`
+	EnduntilKeyword string      `@"enduntil"`
+
+	RcmdObjList []RcmdInterface
+}
+
+func NewUntil(text string) (*Until, *errors.Error) {
+	target, err := NewStruct(text, &Until{})
+	if err != nil {
+		return nil, err
+	}
+	return target.(*Until), nil
+}
+
+func (self *Until) ToString() string {
+	return fmt.Sprintf("%s %s every %d timeout %d", self.Name, self.Condition.ToString(), int(self.Every), int(self.Timeout))
+}
+
+func (self *Until) Prepare(context *ReplayerContext) *errors.Error {
+	rcmdobjlist, err := ConvRcmdList2Obj(self.RcmdList)
+	if err != nil {
+		return err
+	}
+
+	self.RcmdObjList = rcmdobjlist
+	return nil
+}
+
+func (self *Until) Do(context *ReplayerContext) (Void, *errors.Error) {
+	if context == nil {
+		return nil, errors.New("Invalid arguments").AddMsg(self.ToString())
+	}
+
+	if self.Every <= 0 || self.Timeout <= 0 {
+		return nil, errors.New("every, timeout have to greater than zero").AddMsg(self.ToString())
+	}
+
+	/* until 앞의 '; *' comment 가 있으면 comment step 에 결과 기록
+	 * body 의 check 가 comment step 을 가져가지 않도록 poll 전에 선점
+	 */
+	chkResult := context.RecordResult.GetLastCheckStep()
+	if chkResult != nil {
+		chkResult.claimed = true
+	}
+
+	result, controlflow, tries, err := self.poll(context)
+	if err != nil {
+		return nil, err.AddMsg(self.ToString())
+	}
+
+	/* body 의 return 은 while, for 와 같이 step 기록 없이 상위로 전달
+	 */
+	if controlflow != nil {
+		if chkResult != nil {
+			chkResult.claimed = false
+		}
+		return controlflow, nil
+	}
+
+	/* comment 가 없으면 body 수행 후 step 생성
+	 */
+	if chkResult == nil {
+		printflag, depthindent := context.GetResultOptions()
+
+		chkres, err := NewCheckResult("*", self.ToString(), printflag, depthindent)
+		if err != nil {
+			return nil, err.AddMsg(self.ToString())
+		}
+		chkResult = chkres
+
+		step, err := NewStep(chkResult)
+		if err != nil {
+			return nil, err.AddMsg(self.ToString())
+		}
+
+		context.RecordResult.AddStep(step)
+	}
+
+	msg, err := context.ReplaceVariable(self.ToString())
+	if err != nil {
+		return nil, err.AddMsg(self.ToString())
+	}
+	chkResult.Position = GetPositionString(self.Pos)
+
+	if result {
+		chkResult.SetResult(constdef.SUCCESS)
+		chkResult.SetInfo(context.LastSendSessionName, context.LastSend,
+			context.LastOutputSessionName, context.LastOutput, context.ExitCode,
+			fmt.Sprintf("%s, %d tries", msg, tries))
+		return nil, nil
+	}
+
+	/* FAIL, timeout 또는 break 로 condition 을 만족하지 못함
+	 */
+	chkResult.SetResult(constdef.FAIL)
+	chkResult.SetInfo(context.LastSendSessionName, context.LastSend,
+		context.LastOutputSessionName, context.LastOutput, context.ExitCode,
+		fmt.Sprintf("%s, condition is not satisfied in %d seconds, %d tries", msg, int(self.Timeout), tries))
+
+	if context.FailedButContinue {
+		return nil, nil
+	}
+	return CF_RETURN, nil
+}
+
+/* condition 만족 여부, body 의 CF_RETURN, body 수행 횟수 return
+ */
+func (self *Until) poll(context *ReplayerContext) (bool, Void, int, *errors.Error) {
+	varmap := NewVariableMap()
+	context.PushVarMapSlice(varmap)
+	defer context.PopVarMapSlice()
+
+	start := time.Now()
+	timeout := time.Duration(self.Timeout * float64(time.Second))
+	every := time.Duration(self.Every * float64(time.Millisecond))
+
+	for tries := 1; ; tries++ {
+		breakFlag := false
+
+		controlflow, err := PlayRcmdList(self.RcmdObjList, context)
+		if err != nil {
+			return false, nil, tries, err
+		}
+
+		switch controlflow.(type) {
+		case int:
+			switch controlflow.(int) {
+			case CF_BREAK:
+				breakFlag = true
+			case CF_RETURN:
+				return false, controlflow, tries, nil
+			}
+		}
+
+		cond, err := CheckCondition(self.Condition, context)
+		if err != nil {
+			return false, nil, tries, err
+		}
+
+		if cond {
+			return true, nil, tries, nil
+		}
+
+		if breakFlag || time.Now().Sub(start)+every > timeout {
+			return false, nil, tries, nil
+		}
+
+		time.Sleep(every)
+	}
+}
+
+func (self *Until) GetName() string {
+	return self.Name
+}
+
+func (self *Until) Dump() {
+	repr.Println(self)
+}
@@ -4,12 +4,15 @@ import (
 	"discovery/constdef"
 	"discovery/errors"
 	"discovery/fmt"
+	"github.com/alecthomas/participle/lexer"
 	"github.com/alecthomas/repr"
 )
 
 const VersionRcmdStr = "version"
 
 type Version struct {
+	Pos lexer.Position
+
 	Name    string  `@"version"`
 	Version float64 `@NUMBER`
 }
@@ -0,0 +1,91 @@
+package record3
+
+import (
+	"discovery/errors"
+	"discovery/fmt"
+	"github.com/alecthomas/participle/lexer"
+	"github.com/alecthomas/repr"
+)
+
+const WhileRcmdStr = "while"
+
+type While struct {
+	Pos lexer.Position
+
+	Name            string      `@"while"`
+	Condition       *Expression `@@`
+	RcmdList        *RcmdList   `@@`
+	EndwhileKeyword string      `@"endwhile"`
+
+	RcmdObjList []RcmdInterface
+}
+
+func NewWhile(text string) (*While, *errors.Error) {
+	target, err := NewStruct(text, &While{})
+	if err != nil {
+		return nil, err
+	}
+	return target.(*While), nil
+}
+
+func (self *While) ToString() string {
+	return fmt.Sprintf("%s %s", self.Name, self.Condition.ToString())
+}
+
+func (self *While) Prepare(context *ReplayerContext) *errors.Error {
+	rcmdobjlist, err := ConvRcmdList2Obj(self.RcmdList)
+	if err != nil {
+		return err
+	}
+
+	self.RcmdObjList = rcmdobjlist
+	return nil
+}
+
+func (self *While) Do(context *ReplayerContext) (Void, *errors.Error) {
+	if context == nil {
+		return nil, errors.New("Invalid arguments").AddMsg(self.ToString())
+	}
+
+	varmap := NewVariableMap()
+	context.PushVarMapSlice(varmap)
+	defer context.PopVarMapSlice()
+
+	for {
+		cond, err := CheckCondition(self.Condition, context)
+		if err != nil {
+			return nil, err.AddMsg(self.ToString())
+		}
+
+		if !cond {
+			break
+		}
+
+		controlflow, err := PlayRcmdList(self.RcmdObjList, context)
+		if err != nil {
+			return nil, err.AddMsg(self.ToString())
+		}
+
+		switch controlflow.(type) {
+		case int:
+			switch controlflow.(int) {
+			case CF_BREAK:
+				return nil, nil
+			case CF_CONTINUE:
+				continue
+			case CF_RETURN:
+				return controlflow, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+func (self *While) GetName() string {
+	return self.Name
+}
+
+func (self *While) Dump() {
+	repr.Println(self)
+}
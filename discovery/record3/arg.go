@@ -126,6 +126,10 @@ type ReplayerArg struct {
 	CheckGrammar   bool     // rcmd syntax check 만 수행
 	PrintWeb       bool
 	LogDir         string
+	Parallel       int      // 동시 실행 record 개수
+	LockWait       int      // env lease 대기 시간(초)
+	ReportList     []string // result writer 목록, json 과 추가 junit, tap, html
+	Syslog         bool     // report server 로 진행 상황 전송
 }
 
 func ParseReplayerArg() (*ReplayerArg, *errors.Error) {
@@ -141,6 +145,10 @@ func ParseReplayerArg() (*ReplayerArg, *errors.Error) {
 	checkGrammarPtr := flag.Bool("check", false, "check record grammar")
 	printWebPtr := flag.Bool("web", false, "print output for web ui")
 	logDirPtr := flag.String("logdir", "", "log directory")
+	parallelPtr := flag.Int("parallel", 1, "number of records to run concurrently")
+	lockWaitPtr := flag.Int("lockwait", 0, "seconds to wait for env lease, -1 is wait forever")
+	syslogPtr := flag.Bool("syslog", false, "send replay progress to report server")
+	reportPtr := flag.String("report", "", "additional result report format, junit, tap, html. json is always written")
 
 	flag.Parse()
 
@@ -154,6 +162,17 @@ func ParseReplayerArg() (*ReplayerArg, *errors.Error) {
 		return nil, errors.New("Invalid -set, -failset, -rid or -f  arguments")
 	}
 
+	if *parallelPtr < 1 {
+		HelpReplayerArg()
+		return nil, errors.New("Invalid -parallel arguments")
+	}
+
+	reportList, err := ParseReportList(*reportPtr)
+	if err != nil {
+		HelpReplayerArg()
+		return nil, err
+	}
+
 	arg := ReplayerArg{
 		TestName:       *testNamePtr,
 		SetName:        strings.TrimSpace(*setNamePtr),
@@ -165,6 +184,10 @@ func ParseReplayerArg() (*ReplayerArg, *errors.Error) {
 		CheckGrammar:   *checkGrammarPtr,
 		PrintWeb:       *printWebPtr,
 		LogDir:         *logDirPtr,
+		Parallel:       *parallelPtr,
+		LockWait:       *lockWaitPtr,
+		ReportList:     reportList,
+		Syslog:         *syslogPtr,
 	}
 
 	if len(*argsPtr) > 0 && len(*argSepPtr) > 0 {
@@ -194,7 +217,15 @@ func HelpReplayerArg() {
 	fmt.Println("  -check, check record grammar")
 	fmt.Println("  -web output format for web ui")
 	fmt.Println("  -logdir log directory, default current timestamp")
+	fmt.Println("  -parallel number of records to run concurrently, default 1")
+	fmt.Println("  -lockwait seconds to wait for env lease used by other replayer, -1 is wait forever, default 0")
+	fmt.Println("  -report additional result report format separated by ',', junit, tap, html. json is always written")
+	fmt.Println("  -syslog send replay progress to report server(etc/report_server.ini) by syslog")
 	fmt.Println(`ex) replayer -name "patch1" -set network`)
 	fmt.Println(`ex) replayer -name "patch1" -rid "Test/test"`)
 	fmt.Println(`ex) replayer -name "patch1" -f test.record`)
+	fmt.Println(`ex) replayer -name "patch1" -set network -parallel 8`)
+	fmt.Println(`ex) replayer -name "patch1" -set network -lockwait 600`)
+	fmt.Println(`ex) replayer -name "patch1" -set network -report junit,tap,html`)
+	fmt.Println(`ex) replayer -name "patch1" -set network -syslog`)
 }
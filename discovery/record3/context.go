@@ -11,6 +11,7 @@ import (
 	"gopkg.in/resty.v1"
 	"os"
 	"os/exec"
+	"reflect"
 	"regexp"
 	"strconv"
 	"strings"
@@ -226,6 +227,9 @@ type ReplayerContext struct {
 	ForceEnvId     string      // overwrite environment
 	NoEnvHashCheck bool        // true인 경우 env hash check를 하지 않음
 	Env            *config.Env // environment
+	LockWait       int         // env lease 대기 시간(초)
+	EnvLease       *config.Lease
+	ParentEnvLease *config.Lease // require 인 경우 상위 record 의 lease, 같은 env 이면 재사용
 
 	SessionMap map[string]*SessionNode // connect session map
 
@@ -260,6 +264,17 @@ type ReplayerContext struct {
 	 */
 	FunctionMap map[string]FunctionInterface
 
+	/* proc 정의 map, call 수행시 proc 을 찾음
+	 */
+	ProcMap map[string]*Proc
+
+	/* proc call depth 와 proc return 값
+	 * ProcReturnFlag 가 false 인 CF_RETURN 은 record 중단
+	 */
+	ProcDepth       int
+	ProcReturnFlag  bool
+	ProcReturnValue Void
+
 	/* defer rcmd list, defer를 정의할 경우 rcmd 수행시 성공, 실패일 경우 모두 마지막으로 수행됨
 	 */
 	DeferList []*Defer
@@ -320,6 +335,7 @@ func NewReplayerContext(recordname string, category []string, logdir string, out
 		VarRe:       varRe,
 		VarMapSlice: []VariableMap{},
 		FunctionMap: internalFunctionList,
+		ProcMap:     make(map[string]*Proc),
 
 		DeferList: []*Defer{},
 
@@ -595,12 +611,124 @@ func (self *ReplayerContext) DelVariableWithLoadPath(loadfile string) *errors.Er
 	return varmap.DelValueWithLoadPath(loadfile)
 }
 
+/* parallel branch 용 context 생성
+ * LastOutput 등 rcmd 수행 상태와 record result, session map, defer list 는 branch 별로 분리
+ * session map 은 branch session 만 포함
+ * 상위 variable map, proc map 은 복사하여 branch 간 공유하지 않음, variable 은 join 에서 GetVariableChanges 로 변경 내용 반영
+ */
+func (self *ReplayerContext) Fork(rcdresult *RecordResult, sessionName string) *ReplayerContext {
+	context := *self
+
+	context.RecordResult = rcdresult
+	context.DeferList = []*Defer{}
+
+	context.SessionMap = make(map[string]*SessionNode)
+	if session, ok := self.SessionMap[sessionName]; ok {
+		context.SessionMap[sessionName] = session
+	}
+
+	context.RestClientMap = make(map[string]*RestClient)
+	for name, client := range self.RestClientMap {
+		context.RestClientMap[name] = client
+	}
+
+	context.ProcMap = make(map[string]*Proc)
+	for name, proc := range self.ProcMap {
+		context.ProcMap[name] = proc
+	}
+
+	context.VarMapSlice = []VariableMap{}
+	for _, varmap := range self.VarMapSlice {
+		context.VarMapSlice = append(context.VarMapSlice, varmap.Copy())
+	}
+
+	return &context
+}
+
+/* branch 에서 변경한 변수, var map slice index 별 변경, 삭제된 변수
+ */
+type VariableChanges struct {
+	Set []map[string]*Variable
+	Del []map[string]bool
+}
+
+/* fork 한 context 의 var map slice 와 비교하여 변경 내용 return
+ * branch 수행 후 push, pop 이 끝나 var map slice 길이는 같음
+ */
+func (self *ReplayerContext) GetVariableChanges(forked *ReplayerContext) VariableChanges {
+	changes := VariableChanges{}
+
+	for idx, varmap := range self.VarMapSlice {
+		setMap := make(map[string]*Variable)
+		delMap := make(map[string]bool)
+
+		if idx < len(forked.VarMapSlice) {
+			forkedMap := forked.VarMapSlice[idx]
+			for name, variable := range forkedMap {
+				orig, ok := varmap[name]
+				if !ok || orig == nil || variable == nil || !reflect.DeepEqual(orig.Value, variable.Value) {
+					setMap[name] = variable
+				}
+			}
+			for name := range varmap {
+				if _, ok := forkedMap[name]; !ok {
+					delMap[name] = true
+				}
+			}
+		}
+
+		changes.Set = append(changes.Set, setMap)
+		changes.Del = append(changes.Del, delMap)
+	}
+
+	return changes
+}
+
+func (self *ReplayerContext) ApplyVariableChanges(changes VariableChanges) {
+	for idx, varmap := range self.VarMapSlice {
+		if idx >= len(changes.Set) {
+			break
+		}
+		for name, variable := range changes.Set[idx] {
+			varmap[name] = variable
+		}
+		for name := range changes.Del[idx] {
+			delete(varmap, name)
+		}
+	}
+}
+
+/* proc 등록, 같은 이름이면 마지막 정의로 덮어씀
+ */
+func (self *ReplayerContext) SetProc(proc *Proc) *errors.Error {
+	if proc == nil || len(proc.ProcName) == 0 {
+		return errors.New("invalid arguments")
+	}
+
+	if _, ok := self.FunctionMap[proc.ProcName]; ok {
+		return errors.New(fmt.Sprintf("'%s' is internal function name", proc.ProcName))
+	}
+
+	self.ProcMap[proc.ProcName] = proc
+	return nil
+}
+
+func (self *ReplayerContext) GetProc(name string) (*Proc, *errors.Error) {
+	proc, ok := self.ProcMap[name]
+	if !ok {
+		return nil, errors.New(fmt.Sprintf("'%s' proc is not defined", name))
+	}
+	return proc, nil
+}
+
 /* context string dump
  */
 func (self *ReplayerContext) DumpToString() string {
 	return repr.String(self, repr.Indent("  "), repr.OmitEmpty(true),
 		repr.IgnoreGoStringer(), repr.Hide(&os.File{}, &regexp.Regexp{}, &exec.Cmd{},
-			time.Time{}, &RecordResult{}, &Defer{}, &resty.Client{}, &resty.Response{}))
+			time.Time{}, &RecordResult{}, &Defer{}, &resty.Client{}, &resty.Response{},
+			&proc.OutputStream{}, &proc.Screen{}, &proc.SSHTransport{}, &proc.SSHConn{},
+			&proc.SerialTransport{}, &proc.Monitor{}, &config.Lease{}, &Proc{}))
 }
 
 /* context내 session close
@@ -629,6 +757,61 @@ func (self *ReplayerContext) Close() {
 			delete(self.RestClientMap, key)
 		}
 	}
+
+	/* env lease 반환
+	 */
+	if self.EnvLease != nil {
+		self.EnvLease.Release()
+		self.EnvLease = nil
+	}
+}
+
+/* node 의 rest client, 처음 요청하면 생성
+ * 같은 node 는 client 를 재사용하여 cookie 유지
+ */
+func (self *ReplayerContext) GetRestClient(nodename string) (*RestClient, *errors.Error) {
+	if client, ok := self.RestClientMap[nodename]; ok {
+		return client, nil
+	}
+
+	node := self.Env.GetNode(nodename)
+	if node == nil {
+		return nil, errors.New(nodename + " node doesn't exist.")
+	}
+	nodeinfo := node.NodeInfo
+
+	apiPath, err := nodeinfo.GetString("RestApiPath")
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("%s is not a rest node", nodename))
+	}
+
+	jsonpath, err := config.GetLoadPath(apiPath, self.RecordCategory)
+	if err != nil {
+		return nil, err
+	}
+
+	protocol, err := nodeinfo.GetString("RestProtocol")
+	if err != nil {
+		return nil, err
+	}
+
+	ip, err := nodeinfo.GetString("Ip")
+	if err != nil {
+		return nil, err
+	}
+
+	port, err := nodeinfo.GetInt("RestPort")
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := NewRestClient(protocol, ip, port, jsonpath)
+	if err != nil {
+		return nil, err
+	}
+
+	self.RestClientMap[nodename] = client
+	return client, nil
 }
 
 /* 현재 prompt 가 bash 인지 확인
@@ -666,3 +849,12 @@ func (self *ReplayerContext) GetResultOptions() (bool, string) {
 	}
 	return false, ""
 }
+
+/* record result 의 출력 buffer, parallel 실행시 record, branch 별로 출력을 모음
+ */
+func (self *ReplayerContext) GetPrinter() *fmt.Printer {
+	if self.RecordResult != nil {
+		return self.RecordResult.Printer
+	}
+	return nil
+}
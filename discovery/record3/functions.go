@@ -1,13 +1,21 @@
 package record3
 
 import (
+	"discovery/config"
+	"discovery/constdef"
 	"discovery/errors"
 	"discovery/fmt"
+	"discovery/textfsm"
+	"encoding/json"
+	"io/ioutil"
+	"math"
 	"os"
 	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type FunctionInterface interface {
@@ -28,6 +36,54 @@ func NewFunctionList() (map[string]FunctionInterface, *errors.Error) {
 	list["type"] = &FuncType{}
 	list["append"] = &FuncAppend{}
 	list["isdefined"] = &FuncIsdefined{}
+	list["screen"] = &FuncScreen{}
+
+	/* regexp
+	 */
+	list["match"] = &FuncMatch{}
+	list["findall"] = &FuncFindall{}
+	list["replace"] = &FuncReplace{}
+	list["captures"] = &FuncCaptures{}
+
+	/* string
+	 */
+	list["upper"] = &FuncUpper{}
+	list["lower"] = &FuncLower{}
+	list["contains"] = &FuncContains{}
+	list["startswith"] = &FuncStartswith{}
+	list["endswith"] = &FuncEndswith{}
+	list["format"] = &FuncFormat{}
+	list["sprintf"] = &FuncFormat{}
+	list["pad"] = &FuncPad{}
+
+	/* collection
+	 */
+	list["keys"] = &FuncKeys{}
+	list["values"] = &FuncValues{}
+	list["sort"] = &FuncSort{}
+	list["uniq"] = &FuncUniq{}
+	list["reverse"] = &FuncReverse{}
+	list["index"] = &FuncIndex{}
+	list["sum"] = &FuncSum{}
+	list["min"] = &FuncMin{}
+	list["max"] = &FuncMax{}
+
+	/* math
+	 */
+	list["int"] = &FuncInt{}
+	list["round"] = &FuncRound{}
+	list["abs"] = &FuncAbs{}
+
+	/* json, time
+	 */
+	list["json_parse"] = &FuncJsonParse{}
+	list["json_dump"] = &FuncJsonDump{}
+	list["now"] = &FuncNow{}
+	list["time"] = &FuncTime{}
+
+	/* TextFSM template parsing
+	 */
+	list["parse"] = &FuncParse{}
 
 	return list, nil
 }
@@ -761,3 +817,1073 @@ func (self *FuncIsdefined) Do(context *ReplayerContext, parameters []*Expression
 
 	return true
 }
+
+/* session 의 screen 을 line list 로 return
+ * screen("S1"), screen("S1", row)
+ */
+type FuncScreen struct{}
+
+func (self *FuncScreen) Do(context *ReplayerContext, parameters []*Expression) Void {
+	n, err := getParamArgs(context, parameters)
+	if err != nil {
+		return err
+	}
+
+	if len(n) != 1 && len(n) != 2 {
+		return errors.New("invalid arguments")
+	}
+
+	sessionName, ok := n[0].(string)
+	if !ok {
+		return errors.New("invalid session name")
+	}
+
+	sessionnode, err := context.GetSessionNode(sessionName)
+	if err != nil {
+		return err
+	}
+
+	if sessionnode.Proc == nil || sessionnode.Proc.Screen == nil {
+		return errors.New(fmt.Sprintf("%s, screen is not available", sessionName))
+	}
+
+	lines := sessionnode.Proc.Screen.Lines()
+	if len(n) == 1 {
+		list := []Void{}
+		for _, line := range lines {
+			list = append(list, line)
+		}
+		return list
+	}
+
+	row, ok := n[1].(float64)
+	if !ok || int(row) < 0 || int(row) >= len(lines) {
+		return errors.New("invalid row")
+	}
+
+	return lines[int(row)]
+}
+
+/* 함수 인자를 regexp 로 변환, string 인 경우 compile
+ */
+func getRegexpArg(value Void) (*regexp.Regexp, *errors.Error) {
+	switch value.(type) {
+	case *regexp.Regexp:
+		return value.(*regexp.Regexp), nil
+	case string:
+		re, oserr := regexp.Compile(value.(string))
+		if oserr != nil {
+			return nil, errors.New(fmt.Sprintf("%s", oserr))
+		}
+		return re, nil
+	default:
+		return nil, errors.New("argument have to be string or regexp")
+	}
+}
+
+/* 값을 문자열로 변환, number 는 소수점 이하 불필요한 0 제거
+ */
+func valueToString(value Void) string {
+	if f, ok := value.(float64); ok {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+/* 함수 인자 중 number 인자를 list 로 변환
+ * sum([1, 2, 3]), sum(1, 2, 3) 두가지 형태 지원
+ */
+func getValueListArg(args []Void) []Void {
+	if len(args) == 1 && IsList(args[0]) {
+		return args[0].([]Void)
+	}
+	return args
+}
+
+/* 두 값 비교, number 와 string 만 비교 가능
+ * a < b: -1, a == b: 0, a > b: 1
+ */
+func compareValue(a Void, b Void) (int, *errors.Error) {
+	if IsNumeric(a) && IsNumeric(b) {
+		if a.(float64) < b.(float64) {
+			return -1, nil
+		} else if a.(float64) > b.(float64) {
+			return 1, nil
+		}
+		return 0, nil
+	} else if IsString(a) && IsString(b) {
+		return strings.Compare(a.(string), b.(string)), nil
+	}
+	return 0, errors.New(fmt.Sprintf("can't compare %s and %s", valueToString(a), valueToString(b)))
+}
+
+/* 두 값이 같은지 검사
+ */
+func equalValue(a Void, b Void) bool {
+	return reflect.DeepEqual(a, b)
+}
+
+/* map 의 key 를 정렬된 list 로 리턴
+ */
+func sortedMapKeys(m map[Void]Void) []Void {
+	keys := []Void{}
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.SliceStable(keys, func(i, j int) bool {
+		res, err := compareValue(keys[i], keys[j])
+		if err != nil {
+			return valueToString(keys[i]) < valueToString(keys[j])
+		}
+		return res < 0
+	})
+	return keys
+}
+
+/* regexp match 여부
+ * match("abc", r"^a"), match("abc", "^a")
+ */
+type FuncMatch struct{}
+
+func (self *FuncMatch) Do(context *ReplayerContext, parameters []*Expression) Void {
+	n, err := getParamArgs(context, parameters)
+	if err != nil {
+		return err
+	}
+
+	if len(n) != 2 {
+		return errors.New("match function, invalid arguments")
+	}
+
+	str, ok := n[0].(string)
+	if !ok {
+		return errors.New("match function, first argument have to be string")
+	}
+
+	re, err := getRegexpArg(n[1])
+	if err != nil {
+		return err.AddMsg("match function, second")
+	}
+
+	return re.MatchString(str)
+}
+
+/* regexp 에 match 되는 문자열 list 리턴
+ * findall("a1b22c333", r"[0-9]+") -> ["1", "22", "333"]
+ */
+type FuncFindall struct{}
+
+func (self *FuncFindall) Do(context *ReplayerContext, parameters []*Expression) Void {
+	n, err := getParamArgs(context, parameters)
+	if err != nil {
+		return err
+	}
+
+	if len(n) != 2 {
+		return errors.New("findall function, invalid arguments")
+	}
+
+	str, ok := n[0].(string)
+	if !ok {
+		return errors.New("findall function, first argument have to be string")
+	}
+
+	re, err := getRegexpArg(n[1])
+	if err != nil {
+		return err.AddMsg("findall function, second")
+	}
+
+	list := []Void{}
+	for _, e := range re.FindAllString(str, -1) {
+		list = append(list, e)
+	}
+	return list
+}
+
+/* 문자열 치환, regexp 인 경우 $1 등 group 참조 가능
+ * replace("a-b-c", "-", "+"), replace("a1b2", r"([0-9])", "<$1>")
+ */
+type FuncReplace struct{}
+
+func (self *FuncReplace) Do(context *ReplayerContext, parameters []*Expression) Void {
+	n, err := getParamArgs(context, parameters)
+	if err != nil {
+		return err
+	}
+
+	if len(n) != 3 {
+		return errors.New("replace function, invalid arguments")
+	}
+
+	str, ok := n[0].(string)
+	if !ok {
+		return errors.New("replace function, first argument have to be string")
+	}
+
+	newStr, ok := n[2].(string)
+	if !ok {
+		return errors.New("replace function, third argument have to be string")
+	}
+
+	switch n[1].(type) {
+	case string:
+		return strings.Replace(str, n[1].(string), newStr, -1)
+	case *regexp.Regexp:
+		return n[1].(*regexp.Regexp).ReplaceAllString(str, newStr)
+	default:
+		return errors.New("replace function, second argument have to be string or regexp")
+	}
+}
+
+/* regexp 첫번째 match 의 group 값 list 리턴, match 되지 않으면 empty list
+ * named group 이 있는 경우 name - value map 리턴
+ * captures("eth0 UP", r"(\w+) (\w+)") -> ["eth0", "UP"]
+ * captures("eth0 UP", r"(?P<name>\w+) (?P<state>\w+)") -> {"name": "eth0", "state": "UP"}
+ */
+type FuncCaptures struct{}
+
+func (self *FuncCaptures) Do(context *ReplayerContext, parameters []*Expression) Void {
+	n, err := getParamArgs(context, parameters)
+	if err != nil {
+		return err
+	}
+
+	if len(n) != 2 {
+		return errors.New("captures function, invalid arguments")
+	}
+
+	str, ok := n[0].(string)
+	if !ok {
+		return errors.New("captures function, first argument have to be string")
+	}
+
+	re, err := getRegexpArg(n[1])
+	if err != nil {
+		return err.AddMsg("captures function, second")
+	}
+
+	named := false
+	for _, name := range re.SubexpNames() {
+		if len(name) > 0 {
+			named = true
+			break
+		}
+	}
+
+	submatch := re.FindStringSubmatch(str)
+	if named {
+		res := make(map[Void]Void)
+		if submatch == nil {
+			return res
+		}
+		for i, name := range re.SubexpNames() {
+			if i > 0 && len(name) > 0 {
+				res[name] = submatch[i]
+			}
+		}
+		return res
+	}
+
+	list := []Void{}
+	if len(submatch) > 1 {
+		for _, e := range submatch[1:] {
+			list = append(list, e)
+		}
+	}
+	return list
+}
+
+/* 대문자 변환
+ */
+type FuncUpper struct{}
+
+func (self *FuncUpper) Do(context *ReplayerContext, parameters []*Expression) Void {
+	n, err := getParamArgs(context, parameters)
+	if err != nil {
+		return err
+	}
+
+	if len(n) != 1 {
+		return errors.New("upper function, invalid arguments")
+	}
+
+	str, ok := n[0].(string)
+	if !ok {
+		return errors.New("upper function, first argument have to be string")
+	}
+	return strings.ToUpper(str)
+}
+
+/* 소문자 변환
+ */
+type FuncLower struct{}
+
+func (self *FuncLower) Do(context *ReplayerContext, parameters []*Expression) Void {
+	n, err := getParamArgs(context, parameters)
+	if err != nil {
+		return err
+	}
+
+	if len(n) != 1 {
+		return errors.New("lower function, invalid arguments")
+	}
+
+	str, ok := n[0].(string)
+	if !ok {
+		return errors.New("lower function, first argument have to be string")
+	}
+	return strings.ToLower(str)
+}
+
+/* 포함 여부
+ * string: 부분 문자열, array: element, map: key 포함 여부
+ * contains("abc", "b"), contains([1, 2], 2), contains({"a": 1}, "a")
+ */
+type FuncContains struct{}
+
+func (self *FuncContains) Do(context *ReplayerContext, parameters []*Expression) Void {
+	n, err := getParamArgs(context, parameters)
+	if err != nil {
+		return err
+	}
+
+	if len(n) != 2 {
+		return errors.New("contains function, invalid arguments")
+	}
+
+	switch n[0].(type) {
+	case string:
+		sub, ok := n[1].(string)
+		if !ok {
+			return errors.New("contains function, second argument have to be string")
+		}
+		return strings.Contains(n[0].(string), sub)
+	case []Void:
+		for _, e := range n[0].([]Void) {
+			if equalValue(e, n[1]) {
+				return true
+			}
+		}
+		return false
+	case map[Void]Void:
+		for key := range n[0].(map[Void]Void) {
+			if equalValue(key, n[1]) {
+				return true
+			}
+		}
+		return false
+	default:
+		return errors.New("contains function, first argument have to be string, array or map")
+	}
+}
+
+/* prefix 검사
+ */
+type FuncStartswith struct{}
+
+func (self *FuncStartswith) Do(context *ReplayerContext, parameters []*Expression) Void {
+	n, err := getParamArgs(context, parameters)
+	if err != nil {
+		return err
+	}
+
+	if len(n) != 2 {
+		return errors.New("startswith function, invalid arguments")
+	}
+
+	str, ok := n[0].(string)
+	if !ok {
+		return errors.New("startswith function, first argument have to be string")
+	}
+
+	prefix, ok := n[1].(string)
+	if !ok {
+		return errors.New("startswith function, second argument have to be string")
+	}
+	return strings.HasPrefix(str, prefix)
+}
+
+/* suffix 검사
+ */
+type FuncEndswith struct{}
+
+func (self *FuncEndswith) Do(context *ReplayerContext, parameters []*Expression) Void {
+	n, err := getParamArgs(context, parameters)
+	if err != nil {
+		return err
+	}
+
+	if len(n) != 2 {
+		return errors.New("endswith function, invalid arguments")
+	}
+
+	str, ok := n[0].(string)
+	if !ok {
+		return errors.New("endswith function, first argument have to be string")
+	}
+
+	suffix, ok := n[1].(string)
+	if !ok {
+		return errors.New("endswith function, second argument have to be string")
+	}
+	return strings.HasSuffix(str, suffix)
+}
+
+/* printf 형식 문자열 생성
+ * number 는 verb 에 맞게 변환, %d 는 정수, %f 는 실수, %s, %v 는 문자열
+ * format("%s-%03d", "eth", 7) -> "eth-007"
+ */
+type FuncFormat struct{}
+
+var formatVerbRe = regexp.MustCompile(`%[-+# 0]*[0-9]*(\.[0-9]*)?[a-zA-Z%]`)
+
+func (self *FuncFormat) Do(context *ReplayerContext, parameters []*Expression) Void {
+	n, err := getParamArgs(context, parameters)
+	if err != nil {
+		return err
+	}
+
+	if len(n) < 1 {
+		return errors.New("format function, invalid arguments")
+	}
+
+	format, ok := n[0].(string)
+	if !ok {
+		return errors.New("format function, first argument have to be string")
+	}
+
+	args := []interface{}{}
+	i := 1
+	for _, verb := range formatVerbRe.FindAllString(format, -1) {
+		if verb == "%%" {
+			continue
+		}
+
+		if i >= len(n) {
+			return errors.New(fmt.Sprintf("format function, missing argument for %s", verb))
+		}
+
+		arg := n[i]
+		i++
+
+		switch verb[len(verb)-1] {
+		case 'd', 'x', 'X', 'o', 'b', 'c':
+			f, ok := arg.(float64)
+			if !ok {
+				return errors.New(fmt.Sprintf("format function, %s needs number argument", verb))
+			}
+			args = append(args, int64(f))
+		case 'e', 'E', 'f', 'F', 'g', 'G':
+			f, ok := arg.(float64)
+			if !ok {
+				return errors.New(fmt.Sprintf("format function, %s needs number argument", verb))
+			}
+			args = append(args, f)
+		case 't':
+			args = append(args, arg)
+		default:
+			args = append(args, valueToString(arg))
+		}
+	}
+
+	if i != len(n) {
+		return errors.New("format function, too many arguments")
+	}
+
+	return fmt.Sprintf(format, args...)
+}
+
+/* 문자열 padding
+ * width 가 양수이면 오른쪽, 음수이면 왼쪽에 padding, 기본 padding 문자는 space
+ * pad("ab", 5) -> "ab   ", pad("7", -3, "0") -> "007"
+ */
+type FuncPad struct{}
+
+func (self *FuncPad) Do(context *ReplayerContext, parameters []*Expression) Void {
+	n, err := getParamArgs(context, parameters)
+	if err != nil {
+		return err
+	}
+
+	if len(n) != 2 && len(n) != 3 {
+		return errors.New("pad function, invalid arguments")
+	}
+
+	str := valueToString(n[0])
+
+	width, ok := n[1].(float64)
+	if !ok {
+		return errors.New("pad function, second argument have to be number")
+	}
+
+	padChar := " "
+	if len(n) == 3 {
+		padChar, ok = n[2].(string)
+		if !ok || len([]rune(padChar)) != 1 {
+			return errors.New("pad function, third argument have to be one character string")
+		}
+	}
+
+	count := int(math.Abs(width)) - len([]rune(str))
+	if count <= 0 {
+		return str
+	}
+
+	if width < 0 {
+		return strings.Repeat(padChar, count) + str
+	}
+	return str + strings.Repeat(padChar, count)
+}
+
+/* map key 를 정렬된 list 로 리턴
+ */
+type FuncKeys struct{}
+
+func (self *FuncKeys) Do(context *ReplayerContext, parameters []*Expression) Void {
+	n, err := getParamArgs(context, parameters)
+	if err != nil {
+		return err
+	}
+
+	if len(n) != 1 || !IsMap(n[0]) {
+		return errors.New("keys function, first argument have to be map")
+	}
+
+	return sortedMapKeys(n[0].(map[Void]Void))
+}
+
+/* map value 를 key 정렬 순서의 list 로 리턴
+ */
+type FuncValues struct{}
+
+func (self *FuncValues) Do(context *ReplayerContext, parameters []*Expression) Void {
+	n, err := getParamArgs(context, parameters)
+	if err != nil {
+		return err
+	}
+
+	if len(n) != 1 || !IsMap(n[0]) {
+		return errors.New("values function, first argument have to be map")
+	}
+
+	m := n[0].(map[Void]Void)
+	list := []Void{}
+	for _, key := range sortedMapKeys(m) {
+		list = append(list, m[key])
+	}
+	return list
+}
+
+/* array 정렬, number 또는 string array 만 정렬 가능
+ * sort([3, 1, 2]) -> [1, 2, 3], sort(list, true) 는 역순 정렬
+ */
+type FuncSort struct{}
+
+func (self *FuncSort) Do(context *ReplayerContext, parameters []*Expression) Void {
+	n, err := getParamArgs(context, parameters)
+	if err != nil {
+		return err
+	}
+
+	if len(n) != 1 && len(n) != 2 {
+		return errors.New("sort function, invalid arguments")
+	}
+
+	if !IsList(n[0]) {
+		return errors.New("sort function, first argument have to be array")
+	}
+
+	reverseFlag := false
+	if len(n) == 2 {
+		flag, ok := n[1].(bool)
+		if !ok {
+			return errors.New("sort function, second argument have to be bool")
+		}
+		reverseFlag = flag
+	}
+
+	list := append([]Void{}, n[0].([]Void)...)
+	var cmpErr *errors.Error
+	sort.SliceStable(list, func(i, j int) bool {
+		res, err := compareValue(list[i], list[j])
+		if err != nil {
+			cmpErr = err
+			return false
+		}
+		if reverseFlag {
+			return res > 0
+		}
+		return res < 0
+	})
+
+	if cmpErr != nil {
+		return cmpErr.AddMsg("sort function")
+	}
+	return list
+}
+
+/* array 중복 제거, 처음 나온 순서 유지
+ */
+type FuncUniq struct{}
+
+func (self *FuncUniq) Do(context *ReplayerContext, parameters []*Expression) Void {
+	n, err := getParamArgs(context, parameters)
+	if err != nil {
+		return err
+	}
+
+	if len(n) != 1 || !IsList(n[0]) {
+		return errors.New("uniq function, first argument have to be array")
+	}
+
+	list := []Void{}
+	for _, e := range n[0].([]Void) {
+		found := false
+		for _, e2 := range list {
+			if equalValue(e, e2) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, e)
+		}
+	}
+	return list
+}
+
+/* array, string 역순 변환
+ */
+type FuncReverse struct{}
+
+func (self *FuncReverse) Do(context *ReplayerContext, parameters []*Expression) Void {
+	n, err := getParamArgs(context, parameters)
+	if err != nil {
+		return err
+	}
+
+	if len(n) != 1 {
+		return errors.New("reverse function, invalid arguments")
+	}
+
+	switch n[0].(type) {
+	case []Void:
+		src := n[0].([]Void)
+		list := []Void{}
+		for i := len(src) - 1; i >= 0; i-- {
+			list = append(list, src[i])
+		}
+		return list
+	case string:
+		src := []rune(n[0].(string))
+		for i, j := 0, len(src)-1; i < j; i, j = i+1, j-1 {
+			src[i], src[j] = src[j], src[i]
+		}
+		return string(src)
+	default:
+		return errors.New("reverse function, first argument have to be array or string")
+	}
+}
+
+/* array element, 부분 문자열의 위치 리턴, 없으면 -1
+ * index([1, 2, 3], 2) -> 1, index("abc", "c") -> 2
+ */
+type FuncIndex struct{}
+
+func (self *FuncIndex) Do(context *ReplayerContext, parameters []*Expression) Void {
+	n, err := getParamArgs(context, parameters)
+	if err != nil {
+		return err
+	}
+
+	if len(n) != 2 {
+		return errors.New("index function, invalid arguments")
+	}
+
+	switch n[0].(type) {
+	case []Void:
+		for i, e := range n[0].([]Void) {
+			if equalValue(e, n[1]) {
+				return float64(i)
+			}
+		}
+		return float64(-1)
+	case string:
+		sub, ok := n[1].(string)
+		if !ok {
+			return errors.New("index function, second argument have to be string")
+		}
+		idx := strings.Index(n[0].(string), sub)
+		if idx < 0 {
+			return float64(-1)
+		}
+		return float64(len([]rune(n[0].(string)[:idx])))
+	default:
+		return errors.New("index function, first argument have to be array or string")
+	}
+}
+
+/* number 합계
+ * sum([1, 2, 3]), sum(1, 2, 3)
+ */
+type FuncSum struct{}
+
+func (self *FuncSum) Do(context *ReplayerContext, parameters []*Expression) Void {
+	n, err := getParamArgs(context, parameters)
+	if err != nil {
+		return err
+	}
+
+	sum := float64(0)
+	for _, e := range getValueListArg(n) {
+		f, ok := e.(float64)
+		if !ok {
+			return errors.New("sum function, arguments have to be number")
+		}
+		sum += f
+	}
+	return sum
+}
+
+/* 최소값, number 또는 string
+ * min([3, 1, 2]), min(3, 1, 2)
+ */
+type FuncMin struct{}
+
+func (self *FuncMin) Do(context *ReplayerContext, parameters []*Expression) Void {
+	n, err := getParamArgs(context, parameters)
+	if err != nil {
+		return err
+	}
+
+	list := getValueListArg(n)
+	if len(list) == 0 {
+		return errors.New("min function, invalid arguments")
+	}
+
+	res := list[0]
+	for _, e := range list[1:] {
+		cmp, err := compareValue(e, res)
+		if err != nil {
+			return err.AddMsg("min function")
+		}
+		if cmp < 0 {
+			res = e
+		}
+	}
+	return res
+}
+
+/* 최대값, number 또는 string
+ * max([3, 1, 2]), max(3, 1, 2)
+ */
+type FuncMax struct{}
+
+func (self *FuncMax) Do(context *ReplayerContext, parameters []*Expression) Void {
+	n, err := getParamArgs(context, parameters)
+	if err != nil {
+		return err
+	}
+
+	list := getValueListArg(n)
+	if len(list) == 0 {
+		return errors.New("max function, invalid arguments")
+	}
+
+	res := list[0]
+	for _, e := range list[1:] {
+		cmp, err := compareValue(e, res)
+		if err != nil {
+			return err.AddMsg("max function")
+		}
+		if cmp > 0 {
+			res = e
+		}
+	}
+	return res
+}
+
+/* 정수 변환, 소수점 이하 버림
+ * int(3.7) -> 3, int("12") -> 12
+ */
+type FuncInt struct{}
+
+func (self *FuncInt) Do(context *ReplayerContext, parameters []*Expression) Void {
+	n, err := getParamArgs(context, parameters)
+	if err != nil {
+		return err
+	}
+
+	if len(n) != 1 {
+		return errors.New("int function, invalid arguments")
+	}
+
+	switch n[0].(type) {
+	case float64:
+		return math.Trunc(n[0].(float64))
+	case string:
+		f, oserr := strconv.ParseFloat(strings.TrimSpace(n[0].(string)), 64)
+		if oserr != nil {
+			return errors.New(fmt.Sprintf("int function, %s", oserr))
+		}
+		return math.Trunc(f)
+	case bool:
+		if n[0].(bool) {
+			return float64(1)
+		}
+		return float64(0)
+	default:
+		return errors.New("int function, first argument have to be number or string")
+	}
+}
+
+/* 반올림, 소수점 이하 자리수 지정 가능
+ * round(2.5) -> 3, round(3.14159, 2) -> 3.14
+ */
+type FuncRound struct{}
+
+func (self *FuncRound) Do(context *ReplayerContext, parameters []*Expression) Void {
+	n, err := getParamArgs(context, parameters)
+	if err != nil {
+		return err
+	}
+
+	if len(n) != 1 && len(n) != 2 {
+		return errors.New("round function, invalid arguments")
+	}
+
+	f, ok := n[0].(float64)
+	if !ok {
+		return errors.New("round function, first argument have to be number")
+	}
+
+	digits := float64(0)
+	if len(n) == 2 {
+		digits, ok = n[1].(float64)
+		if !ok {
+			return errors.New("round function, second argument have to be number")
+		}
+	}
+
+	scale := math.Pow(10, math.Trunc(digits))
+	return math.Round(f*scale) / scale
+}
+
+/* 절대값
+ */
+type FuncAbs struct{}
+
+func (self *FuncAbs) Do(context *ReplayerContext, parameters []*Expression) Void {
+	n, err := getParamArgs(context, parameters)
+	if err != nil {
+		return err
+	}
+
+	if len(n) != 1 {
+		return errors.New("abs function, invalid arguments")
+	}
+
+	f, ok := n[0].(float64)
+	if !ok {
+		return errors.New("abs function, first argument have to be number")
+	}
+	return math.Abs(f)
+}
+
+/* json 문자열을 map, array 등 변수 값으로 변환
+ */
+type FuncJsonParse struct{}
+
+func (self *FuncJsonParse) Do(context *ReplayerContext, parameters []*Expression) Void {
+	n, err := getParamArgs(context, parameters)
+	if err != nil {
+		return err
+	}
+
+	if len(n) != 1 {
+		return errors.New("json_parse function, invalid arguments")
+	}
+
+	str, ok := n[0].(string)
+	if !ok {
+		return errors.New("json_parse function, first argument have to be string")
+	}
+
+	var data interface{}
+	oserr := json.Unmarshal([]byte(str), &data)
+	if oserr != nil {
+		return errors.New(fmt.Sprintf("json_parse function, %s", oserr))
+	}
+
+	vari, err := NewVariable("_", data, "")
+	if err != nil {
+		return err.AddMsg("json_parse function")
+	}
+	return vari.Value
+}
+
+/* 변수 값을 json 문자열로 변환
+ * json_dump(value), json_dump(value, true) 는 indent 적용
+ */
+type FuncJsonDump struct{}
+
+func (self *FuncJsonDump) Do(context *ReplayerContext, parameters []*Expression) Void {
+	n, err := getParamArgs(context, parameters)
+	if err != nil {
+		return err
+	}
+
+	if len(n) != 1 && len(n) != 2 {
+		return errors.New("json_dump function, invalid arguments")
+	}
+
+	indentFlag := false
+	if len(n) == 2 {
+		flag, ok := n[1].(bool)
+		if !ok {
+			return errors.New("json_dump function, second argument have to be bool")
+		}
+		indentFlag = flag
+	}
+
+	data := ConvVoidToStringMap(n[0])
+
+	var out []byte
+	var oserr error
+	if indentFlag {
+		out, oserr = json.MarshalIndent(data, "", "  ")
+	} else {
+		out, oserr = json.Marshal(data)
+	}
+	if oserr != nil {
+		return errors.New(fmt.Sprintf("json_dump function, %s", oserr))
+	}
+	return string(out)
+}
+
+/* 현재 시간을 unix epoch 초 단위로 리턴, 소수점 이하 millisecond
+ */
+type FuncNow struct{}
+
+func (self *FuncNow) Do(context *ReplayerContext, parameters []*Expression) Void {
+	if len(parameters) != 0 {
+		return errors.New("now function, invalid arguments")
+	}
+
+	return float64(time.Now().UnixNano()/int64(time.Millisecond)) / 1000
+}
+
+/* 시간 변환, layout 은 golang time layout 사용
+ * time() -> 현재 시간 문자열
+ * time(epoch), time(epoch, layout) -> 시간 문자열
+ * time("2006-01-02 15:04:05"), time(str, layout) -> epoch
+ */
+type FuncTime struct{}
+
+func (self *FuncTime) Do(context *ReplayerContext, parameters []*Expression) Void {
+	n, err := getParamArgs(context, parameters)
+	if err != nil {
+		return err
+	}
+
+	if len(n) > 2 {
+		return errors.New("time function, invalid arguments")
+	}
+
+	layout := constdef.TIME_FUNCTION_LAYOUT
+	if len(n) == 2 {
+		str, ok := n[1].(string)
+		if !ok {
+			return errors.New("time function, second argument have to be string")
+		}
+		layout = str
+	}
+
+	if len(n) == 0 {
+		return time.Now().Format(layout)
+	}
+
+	switch n[0].(type) {
+	case float64:
+		epoch := n[0].(float64)
+		sec := math.Floor(epoch)
+		nsec := math.Round((epoch - sec) * 1e9)
+		return time.Unix(int64(sec), int64(nsec)).Format(layout)
+	case string:
+		t, oserr := time.ParseInLocation(layout, n[0].(string), time.Local)
+		if oserr != nil {
+			return errors.New(fmt.Sprintf("time function, %s", oserr))
+		}
+		return float64(t.UnixNano()/int64(time.Millisecond)) / 1000
+	default:
+		return errors.New("time function, first argument have to be number or string")
+	}
+}
+
+/* TextFSM 호환 template 으로 CLI output parsing, Value name - 값 map list 리턴
+ * output 은 문자열 또는 line list, template 경로는 load 와 같이 config.GetLoadPath 사용
+ * parse(output_string, "templates/show_int.tpl")
+ */
+type FuncParse struct{}
+
+func (self *FuncParse) Do(context *ReplayerContext, parameters []*Expression) Void {
+	n, err := getParamArgs(context, parameters)
+	if err != nil {
+		return err
+	}
+
+	if len(n) != 2 {
+		return errors.New("parse function, invalid arguments")
+	}
+
+	var text string
+	switch n[0].(type) {
+	case string:
+		text = n[0].(string)
+	case []Void:
+		lines := []string{}
+		for _, e := range n[0].([]Void) {
+			lines = append(lines, valueToString(e))
+		}
+		text = strings.Join(lines, "\n")
+	default:
+		return errors.New("parse function, first argument have to be string or array")
+	}
+
+	templateFile, ok := n[1].(string)
+	if !ok {
+		return errors.New("parse function, second argument have to be string")
+	}
+
+	path, err := config.GetLoadPath(templateFile, context.RecordCategory)
+	if err != nil {
+		return err.AddMsg("parse function")
+	}
+
+	data, oserr := ioutil.ReadFile(path)
+	if oserr != nil {
+		return errors.New(fmt.Sprintf("parse function, %s", oserr))
+	}
+
+	template, err := textfsm.NewTemplate(string(data))
+	if err != nil {
+		return err.AddMsg(fmt.Sprintf("parse function, %s", templateFile))
+	}
+
+	records, err := template.Parse(text)
+	if err != nil {
+		return err.AddMsg(fmt.Sprintf("parse function, %s", templateFile))
+	}
+
+	list := []interface{}{}
+	for _, record := range records {
+		list = append(list, record)
+	}
+
+	vari, err := NewVariable("_", list, "")
+	if err != nil {
+		return err.AddMsg("parse function")
+	}
+	return vari.Value
+}
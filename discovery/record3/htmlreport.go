@@ -0,0 +1,231 @@
+package record3
+
+import (
+	"bytes"
+	"discovery/config"
+	"discovery/constdef"
+	"discovery/errors"
+	"discovery/fmt"
+	"discovery/utils"
+	"encoding/json"
+	"html/template"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+/* results.json 의 step result 는 Type 에 따라 변환
+ */
+func (self *Step) UnmarshalJSON(data []byte) error {
+	type stepJsonForm struct {
+		Type   string
+		Result json.RawMessage
+	}
+
+	form := stepJsonForm{}
+	if goerr := json.Unmarshal(data, &form); goerr != nil {
+		return goerr
+	}
+
+	var result Void
+	switch form.Type {
+	case "check":
+		result = &CheckResult{}
+	case "require":
+		result = &RecordResult{}
+	case "checker":
+		result = &CheckerResult{}
+	case "error":
+		result = &ErrorResult{}
+	default:
+		return fmt.Errorf("'%s' is invalid step type", form.Type)
+	}
+
+	if goerr := json.Unmarshal(form.Result, result); goerr != nil {
+		return goerr
+	}
+
+	self.Type = form.Type
+	self.Result = result
+	return nil
+}
+
+/* results.json 파일 load, LogDir 는 results.json 디렉토리
+ */
+func LoadResult(path string) (*Result, *errors.Error) {
+	data, goerr := os.ReadFile(path)
+	if goerr != nil {
+		return nil, errors.New(fmt.Sprintf("%s", goerr))
+	}
+
+	result := Result{}
+	if goerr := json.Unmarshal(data, &result); goerr != nil {
+		return nil, errors.New(fmt.Sprintf("%s, %s", path, goerr))
+	}
+	result.LogDir = filepath.Dir(path)
+
+	return &result, nil
+}
+
+/* timestamp 이면 contents results 디렉토리에서 찾음
+ * 디렉토리이면 디렉토리의 results.json
+ */
+func GetResultJsonPath(resultPath string) (string, *errors.Error) {
+	if info, goerr := os.Stat(resultPath); goerr == nil {
+		if info.IsDir() {
+			return filepath.Join(resultPath, "results.json"), nil
+		}
+		return resultPath, nil
+	}
+
+	dir, err := config.GetContentsResultsDir()
+	if err != nil {
+		return "", err
+	}
+
+	path := fmt.Sprintf("%s/%s/results.json", dir, resultPath)
+	if !utils.IsExist(path) {
+		return "", errors.New(fmt.Sprintf("%s, result is not exist", resultPath))
+	}
+
+	return path, nil
+}
+
+/* record log 디렉토리의 session log 파일, results.html 기준 상대 경로
+ */
+func (self *Result) GetRecordLogFiles(seq uint32) []string {
+	logFiles := []string{}
+
+	recordLogDir := fmt.Sprintf("%s/%d", self.LogDir, seq)
+	filepath.Walk(recordLogDir, func(path string, info os.FileInfo, goerr error) error {
+		if goerr != nil {
+			return nil
+		}
+		if !info.IsDir() && strings.HasSuffix(path, ".log") {
+			if rel, goerr := filepath.Rel(self.LogDir, path); goerr == nil {
+				logFiles = append(logFiles, rel)
+			}
+		}
+		return nil
+	})
+	sort.Strings(logFiles)
+
+	return logFiles
+}
+
+/* 단일 html 파일 report
+ */
+func (self *Result) WriteHtml() *errors.Error {
+	funcMap := template.FuncMap{
+		"logfiles": self.GetRecordLogFiles,
+		"result": func(resultCode uint8) string {
+			switch resultCode {
+			case constdef.SUCCESS:
+				return "success"
+			case constdef.FAIL:
+				return "fail"
+			case constdef.NOP:
+				return "nop"
+			default:
+				return "na"
+			}
+		},
+		"join": func(lines []string) string {
+			return strings.Join(lines, "\n")
+		},
+	}
+
+	tmpl, goerr := template.New("report").Funcs(funcMap).Parse(HTML_REPORT_TEMPLATE)
+	if goerr != nil {
+		return errors.New(fmt.Sprintf("%s", goerr))
+	}
+
+	buffer := bytes.Buffer{}
+	if goerr := tmpl.Execute(&buffer, self); goerr != nil {
+		return errors.New(fmt.Sprintf("%s", goerr))
+	}
+
+	return self.writeReportFile("html", "results.html", buffer.Bytes())
+}
+
+var HTML_REPORT_TEMPLATE string = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Testname}} - {{.Setname}}</title>
+<style>
+body { font-family: sans-serif; font-size: 14px; margin: 20px; }
+table.summary td { padding: 2px 12px 2px 0; }
+details { margin: 4px 0 4px 16px; }
+summary { cursor: pointer; }
+pre { background: #f4f4f4; padding: 6px; margin: 4px 0 4px 16px; white-space: pre-wrap; }
+.success { color: #2e7d32; }
+.fail { color: #c62828; }
+.error { color: #ef6c00; }
+.na, .nop { color: #757575; }
+.comment { margin: 4px 0 4px 16px; font-weight: bold; }
+.info { margin: 2px 0 2px 16px; color: #424242; }
+</style>
+</head>
+<body>
+<h2>{{.Testname}}</h2>
+<table class="summary">
+<tr><td>Set</td><td>{{.Setname}} ({{.Settype}})</td></tr>
+<tr><td>Timestamp</td><td>{{.Timestamp}}</td></tr>
+<tr><td>Records</td><td>{{.Recordcount}}</td></tr>
+<tr><td>Success steps</td><td class="success">{{.Successcount}}</td></tr>
+<tr><td>Fail steps</td><td class="fail">{{.Failcount}}</td></tr>
+<tr><td>Errors</td><td class="error">{{.Errorcount}}</td></tr>
+</table>
+{{- range .RecordResults}}
+<details{{if or .Failcount .Errorcount}} open{{end}}>
+<summary class="{{template "status" .}}">{{printf "%05d" .Seq}} {{.Name}} - success {{.Successcount}}, fail {{.Failcount}}, error {{.Errorcount}}, {{printf "%d" .RunTime}}ms</summary>
+<div class="info">start: {{.StartTime.Format "2006-01-02 15:04:05"}}</div>
+{{with logfiles .Seq}}<div class="info">logs:{{range .}} <a href="{{.}}">{{.}}</a>{{end}}</div>{{end}}
+{{- template "record" .}}
+</details>
+{{- end}}
+</body>
+</html>
+{{define "status"}}{{if .Errorcount}}error{{else if .Failcount}}fail{{else}}success{{end}}{{end}}
+{{define "record"}}
+{{- range .Steps}}
+{{- if eq .Type "check"}}{{with .GetCheckResult}}
+{{- if eq .CommentType "*"}}
+<details>
+<summary class="{{result .ResultCode}}">[*] {{.Comment}} - {{result .ResultCode}}</summary>
+{{if .CheckCondition}}<div class="info">check condition: {{.CheckCondition}}</div>{{end}}
+{{if .Position}}<div class="info">position: {{.Position}}</div>{{end}}
+{{if .LastSend}}<div class="info">last send{{if .LastSendSessionName}} ({{.LastSendSessionName}}){{end}}: {{.LastSend}}</div>{{end}}
+{{if .ExitCode | ne -1}}<div class="info">exit_code: {{.ExitCode}}</div>{{end}}
+{{if .OutputString}}<div class="info">output_string{{if .OutputSessionName}} ({{.OutputSessionName}}){{end}}:</div><pre>{{join .OutputString}}</pre>{{end}}
+</details>
+{{- else if .Comment}}
+<div class="comment">{{.CommentType}} {{.Comment}}</div>
+{{- end}}
+{{- end}}{{else if eq .Type "checker"}}{{with .GetCheckerResult}}
+<details>
+<summary class="{{result .ResultCode}}">[c] {{.CheckerPath}} - {{result .ResultCode}}</summary>
+<pre>{{join .OutputString}}</pre>
+</details>
+{{- end}}{{else if eq .Type "error"}}{{with .GetErrorResult}}
+<details open>
+<summary class="error">[e] error</summary>
+<pre>{{.Error.ToString false}}</pre>
+</details>
+{{- end}}{{else if eq .Type "require"}}{{with .GetRequireResult}}
+<details{{if or .Failcount .Errorcount}} open{{end}}>
+<summary class="{{template "status" .}}">[r] require {{.Name}} - success {{.Successcount}}, fail {{.Failcount}}, error {{.Errorcount}}, {{printf "%d" .RunTime}}ms</summary>
+{{- template "record" .}}
+</details>
+{{- end}}{{end}}
+{{- end}}
+{{- if .ErrorResult}}{{with .ErrorResult.Error}}
+<details open>
+<summary class="error">record error</summary>
+<pre>{{.ToString false}}</pre>
+</details>
+{{- end}}{{end}}
+{{- end}}
+`
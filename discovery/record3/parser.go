@@ -3,28 +3,102 @@ package record3
 import (
 	"discovery/errors"
 	"discovery/fmt"
+	"io"
+	"regexp"
+	"strings"
 
 	"github.com/alecthomas/participle"
 	"github.com/alecthomas/participle/lexer"
 )
 
+/* lexer 에서 예약된 function 이름, 변수 이름으로 사용 불가
+ * 이외의 internal function 은 IDENT 로 처리, 뒤에 "(" 가 오는 경우에만 function 으로 호출
+ */
+const functionTokenNames = `len|num|str|exist|expr|split|join|trim|filter|type|append|isdefined`
+
+var functionTokenRe = regexp.MustCompile(`^(` + functionTokenNames + `)$`)
+
+func IsFunctionToken(name string) bool {
+	return functionTokenRe.MatchString(name)
+}
+
 /* XXX: lexer regex token 스트링 순서 유지
  */
-var RcmdLexer = lexer.Must(lexer.Regexp(`(?m)` +
+var RcmdLexer = &rcmdLexerDefinition{lexer.Must(lexer.Regexp(`(?m)` +
 	`(\s+)` +
 	`|(^[#].*$)` +
 	`|(?P<COMMENT>;.*$)` +
-	`|(?P<RCMD>(?i)\b(BASHSETENV|BP|CHECK|CLOSE|CONNECT|DEBUG|DEFER|ENVIRONMENT|EOL|ERROR|EXPECT|FOR|GET|IF|LOAD|LOG|PUT|REQUIRE|SCRIPT|SEND|SET|SETA|SLEEP|SPAWN|TABLE|UNLOAD|UNSET|VERSION)\b)` +
-	`|(?P<KEYWORD>(?i)\b(CR|LF|CRLF|INI|RANGE|ON|OFF|CSV|ROW|IN|TRUE|FALSE|NULL|NIL|NONE|AND|OR|NOT|ELSEIF|ELSE|ENDIF|ENDDEFER|ENDFOR|ENDTABLE|BREAK|CONTINUE|RETURN|STEP|BOTH_VARIABLE_NAME|IGNORE_SECTION_NAME|COMPAT_INI|LOGIN|LOGOUT|RFC2544|NORMAL|REQ)\b)` +
-	`|(?P<FUNCTION>\b(len|num|str|exist|expr|split|join|trim|filter|type|append|isdefined)\b)` +
+	`|(?P<RCMD>(?i)\b(BASHSETENV|BP|CALL|CHECK|CLOSE|CONNECT|DEBUG|DEFER|ENVIRONMENT|EOL|ERROR|EXPECT|FOR|GET|IF|IMPORT|INCLUDE|LOAD|LOG|MONITOR|PARALLEL|PROC|PUT|REQUIRE|SCRIPT|SEND|SET|SETA|SLEEP|SPAWN|TABLE|TRY|UNLOAD|UNSET|UNTIL|VERSION|WHILE)\b)` +
+	`|(?P<KEYWORD>(?i)\b(CR|LF|CRLF|INI|RANGE|ON|OFF|CSV|ROW|IN|TRUE|FALSE|NULL|NIL|NONE|AND|OR|NOT|ELSEIF|ELSE|ENDIF|ENDDEFER|ENDFOR|ENDPARALLEL|ENDPROC|ENDTABLE|ENDTRY|ENDUNTIL|ENDWHILE|CATCH|FINALLY|ENDEXPECT|BREAK|CONTINUE|RETURN|STEP|BOTH_VARIABLE_NAME|IGNORE_SECTION_NAME|COMPAT_INI|LOGIN|LOGOUT|RFC2544|NORMAL|REQ)\b)` +
+	`|(?P<FUNCTION>\b(` + functionTokenNames + `)\b)` +
 	`|(?P<IDENT>[a-zA-Z_][a-zA-Z0-9_:]*)` +
 	`|(?P<OPERATORS>[-+*/%,.()=<>!~:;])` +
 	`|(?P<NUMBER>\d+(\.\d+)?)` +
 	"|(?P<STRING>'(?:[^'\\\\]|\\\\.)*'|\"(?:[^\"\\\\]|\\\\.)*\"|`(?:[^`\\\\]|\\\\.)*`)" +
 	`|(?P<BRACKET>[\[\]\{\}])`,
-))
+))}
+
+/* 줄의 처음 token 인 경우에만 rcmd, keyword 로 처리하는 이름
+ * 그 외 위치에서는 IDENT 로 변수 이름으로 사용 가능
+ */
+var contextualKeywords = map[string]string{
+	"rest":   "RCMD",
+	"branch": "KEYWORD",
+	"case":   "KEYWORD",
+}
+
+type rcmdLexerDefinition struct {
+	lexer.Definition
+}
+
+func (self *rcmdLexerDefinition) Lex(reader io.Reader) (lexer.Lexer, error) {
+	lex, oserr := self.Definition.Lex(reader)
+	if oserr != nil {
+		return nil, oserr
+	}
+	return &rcmdLexer{Lexer: lex, symbols: self.Symbols()}, nil
+}
+
+/* 이전 줄의 { @IDENT } 등에 contextual keyword 가 포함되지 않도록 token type 변경
+ */
+type rcmdLexer struct {
+	lexer.Lexer
+	symbols map[string]rune
+	line    int /* 이전 token 의 마지막 line */
+}
+
+func (self *rcmdLexer) Next() (lexer.Token, error) {
+	token, oserr := self.Lexer.Next()
+	if oserr != nil || token.EOF() {
+		return token, oserr
+	}
+
+	if token.Pos.Line != self.line && token.Type == self.symbols["IDENT"] {
+		if symbol, ok := contextualKeywords[token.Value]; ok {
+			token.Type = self.symbols[symbol]
+		}
+	}
+	self.line = token.Pos.Line + strings.Count(token.Value, "\n")
+
+	return token, nil
+}
+
+/* parsing 할 record 파일 이름, lexer position 의 Filename 으로 사용
+ */
+type recordReader struct {
+	*strings.Reader
+	filename string
+}
+
+func (self *recordReader) Name() string {
+	return self.filename
+}
 
 func RcmdParser(recordText string, grammar interface{}, target interface{}) *errors.Error {
+	return RcmdFileParser(recordText, "", grammar, target)
+}
+
+func RcmdFileParser(recordText string, filename string, grammar interface{}, target interface{}) *errors.Error {
 	parser, oserr := participle.Build(
 		grammar,
 		participle.Lexer(RcmdLexer),
@@ -33,7 +107,7 @@ func RcmdParser(recordText string, grammar interface{}, target interface{}) *err
 		return errors.New(fmt.Sprintf("%s", oserr))
 	}
 
-	oserr = parser.ParseString(recordText, target)
+	oserr = parser.Parse(&recordReader{strings.NewReader(recordText), filename}, target)
 	if oserr != nil {
 		return errors.New(fmt.Sprintf("%s", oserr))
 	}
@@ -42,12 +116,18 @@ func RcmdParser(recordText string, grammar interface{}, target interface{}) *err
 }
 
 func NewStruct(text string, grammar Void) (Void, *errors.Error) {
+	return NewStructWithFilename(text, "", grammar)
+}
+
+/* filename 이 있으면 parsing error, rcmd Pos 에 file:line:column 표시
+ */
+func NewStructWithFilename(text string, filename string, grammar Void) (Void, *errors.Error) {
 	if len(text) == 0 || grammar == nil {
 		return nil, errors.New("invalid arguments")
 	}
 
 	target := grammar
-	err := RcmdParser(text, grammar, target)
+	err := RcmdFileParser(text, filename, grammar, target)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,44 @@
+package record3
+
+import (
+	"testing"
+)
+
+/* contextual keyword 는 줄의 처음에서만 rcmd, keyword 로 처리
+ */
+func TestContextualKeywords(t *testing.T) {
+	/* record 와 최상위 rcmd 수
+	 */
+	records := map[string]int{
+		`set rest 1
+set with rest + 1
+unset rest with
+rest "api" "login" r with {"username": with}`: 4,
+		`set branch "main"
+parallel
+branch S1
+    unset branch
+branch S2
+    set a branch
+endparallel
+unset branch`: 3,
+		`set case 1
+expect 5 S1
+case "yes"
+    call p()
+case "no"
+    unset case
+endexpect`: 2,
+	}
+
+	for record, count := range records {
+		rcmdlist, err := NewStruct(record, &RcmdList{})
+		if err != nil {
+			t.Errorf("%s: %s", record, err.ToString(false))
+			continue
+		}
+		if len(rcmdlist.(*RcmdList).List) != count {
+			t.Errorf("%s: %d rcmds, want %d", record, len(rcmdlist.(*RcmdList).List), count)
+		}
+	}
+}
@@ -742,6 +742,14 @@ func (self *Primary) Do(context *ReplayerContext) (Void, *errors.Error) {
 		}
 		return re, nil
 	} else if self.Variable != nil {
+		/* 예약된 function 이름이 아니면 같은 이름의 변수 우선
+		 */
+		if !IsFunctionToken(*self.Variable) {
+			if value, err := context.GetVariableValue(*self.Variable); err == nil {
+				return value, nil
+			}
+		}
+
 		value2, ok := context.FunctionMap[*self.Variable]
 		if !ok {
 			value, err := context.GetVariableValue(*self.Variable)
@@ -919,6 +927,14 @@ func (self *PrimValue) Do(context *ReplayerContext) (Void, *errors.Error) {
 		return nil, errors.New("invalid primary value")
 	}
 
+	/* 이름 뒤에 "(" 가 오면 같은 이름의 변수가 있어도 function 호출
+	 */
+	if self.Value.Variable != nil && self.Param != nil && self.Param.FuncParam != nil {
+		if f, ok := context.FunctionMap[*self.Value.Variable]; ok {
+			return self.Param.Do(f, context)
+		}
+	}
+
 	value, err := self.Value.Do(context)
 	if err != nil {
 		return nil, err
@@ -6,6 +6,7 @@ import (
 	"discovery/errors"
 	"discovery/fmt"
 	"discovery/utils"
+	"github.com/alecthomas/participle/lexer"
 	"io/ioutil"
 	"os"
 	"os/exec"
@@ -44,38 +45,50 @@ type RcmdList struct {
  * Rcmd는 하나의 요소만 갖음
  */
 type Rcmd struct {
-	Bashsetenv  *Bashsetenv  `(@@`
-	BP          *BP          `|@@`
-	Break       *Break       `|@@`
-	Check       *Check       `|@@`
-	Close       *Close       `|@@`
-	Comment     *Comment     `|@@`
-	Connect     *Connect     `|@@`
-	Continue    *Continue    `|@@`
-	Debug       *Debug       `|@@`
-	Defer       *Defer       `|@@`
-	Environment *Environment `|@@`
-	Eol         *Eol         `|@@`
-	Error       *Error       `|@@`
-	Expect      *Expect      `|@@`
-	For         *For         `|@@`
-	Get         *Get         `|@@`
-	If          *If          `|@@`
-	Load        *Load        `|@@`
-	Log         *Log         `|@@`
-	Put         *Put         `|@@`
-	Return      *Return      `|@@`
-	Require     *Require     `|@@`
-	Script      *Script      `|@@`
-	Send        *Send        `|@@`
-	Set         *Set         `|@@`
-	Seta        *Seta        `|@@`
-	Sleep       *Sleep       `|@@`
-	Spawn       *Spawn       `|@@`
-	Table       *Table       `|@@`
-	Unload      *Unload      `|@@`
-	Unset       *Unset       `|@@`
-	Version     *Version     `|@@)`
+	Bashsetenv   *Bashsetenv   `(@@`
+	BP           *BP           `|@@`
+	Break        *Break        `|@@`
+	Call         *Call         `|@@`
+	Check        *Check        `|@@`
+	Close        *Close        `|@@`
+	Comment      *Comment      `|@@`
+	Connect      *Connect      `|@@`
+	Continue     *Continue     `|@@`
+	Debug        *Debug        `|@@`
+	Defer        *Defer        `|@@`
+	Environment  *Environment  `|@@`
+	Eol          *Eol          `|@@`
+	Error        *Error        `|@@`
+	Expect       *Expect       `|@@`
+	ExpectBlock  *ExpectBlock  `|@@`
+	ExpectScreen *ExpectScreen `|@@`
+	For          *For          `|@@`
+	Get          *Get          `|@@`
+	If           *If           `|@@`
+	Import       *Import       `|@@`
+	Include      *Include      `|@@`
+	Load         *Load         `|@@`
+	Log          *Log          `|@@`
+	Monitor      *Monitor      `|@@`
+	Parallel     *Parallel     `|@@`
+	Proc         *Proc         `|@@`
+	Put          *Put          `|@@`
+	Return       *Return       `|@@`
+	Require      *Require      `|@@`
+	Rest         *Rest         `|@@`
+	Script       *Script       `|@@`
+	Send         *Send         `|@@`
+	Set          *Set          `|@@`
+	Seta         *Seta         `|@@`
+	Sleep        *Sleep        `|@@`
+	Spawn        *Spawn        `|@@`
+	Table        *Table        `|@@`
+	Try          *Try          `|@@`
+	Unload       *Unload       `|@@`
+	Unset        *Unset        `|@@`
+	Until        *Until        `|@@`
+	Version      *Version      `|@@`
+	While        *While        `|@@)`
 }
 
 func NewRecord(name string, category []string) (*Record, *errors.Error) {
@@ -94,12 +107,25 @@ func NewRecord(name string, category []string) (*Record, *errors.Error) {
 		return nil, e
 	}
 
-	rcmdlist, err := NewStruct(text, &RcmdList{})
+	recordPath, err := config.GetContentsRecordPath(name, category)
+	if err != nil {
+		return nil, err
+	}
+
+	rcmdlist, err := NewStructWithFilename(text, filepath.Clean(recordPath), &RcmdList{})
 	if err != nil {
 		return nil, err
 	}
 
 	record.RcmdList = rcmdlist.(*RcmdList)
+
+	/* include 는 parsing 시점에 include 파일의 rcmd 로 치환
+	 */
+	err = ExpandInclude(record.RcmdList, category, []string{filepath.Clean(recordPath)})
+	if err != nil {
+		return nil, err
+	}
+
 	record.RcmdObjList, err = ConvRcmdList2Obj(record.RcmdList)
 	if err != nil {
 		return nil, err
@@ -121,7 +147,36 @@ func (self *Record) load() (string, *errors.Error) {
 	return string(data), nil
 }
 
+/* record 의 environment id, environment rcmd 가 없으면 ""
+ */
+func (self *Record) GetEnvId(forceEnvId string) string {
+	for _, rcmd := range self.RcmdObjList {
+		environment, ok := rcmd.(*Environment)
+		if !ok {
+			continue
+		}
+
+		envid := utils.Unquote(environment.EnvId)
+		if len(forceEnvId) > 0 {
+			forceEnvid, err := GetForceEnvId(envid, forceEnvId)
+			if err == nil {
+				envid = forceEnvid
+			}
+		}
+		return envid
+	}
+
+	return ""
+}
+
 func (self *Record) Play(context *ReplayerContext) *errors.Error {
+	/* call 이 proc 정의보다 앞에 있어도 호출 가능하도록 재생 전 proc 등록
+	 */
+	err := RegisterProcList(self.RcmdList, context)
+	if err != nil {
+		return PlayDeferList(context, err)
+	}
+
 	controlflow, err := PlayRcmdList(self.RcmdObjList, context)
 	if err != nil {
 		return PlayDeferList(context, err)
@@ -288,7 +343,7 @@ func PlayRcmdList(rcmdObjList []RcmdInterface, context *ReplayerContext) (Void,
 	for _, rcmdObj := range rcmdObjList {
 		err := rcmdObj.Prepare(context)
 		if err != nil {
-			return nil, err
+			return nil, err.SetPosition(GetRcmdPosition(rcmdObj))
 		}
 	}
 
@@ -297,7 +352,7 @@ func PlayRcmdList(rcmdObjList []RcmdInterface, context *ReplayerContext) (Void,
 	for _, rcmdObj := range rcmdObjList {
 		controlflow, err := rcmdObj.Do(context)
 		if err != nil {
-			return controlflow, err
+			return controlflow, err.SetPosition(GetRcmdPosition(rcmdObj))
 		}
 
 		/* control flow 가 nil, NOP 이 아니면
@@ -315,6 +370,34 @@ func PlayRcmdList(rcmdObjList []RcmdInterface, context *ReplayerContext) (Void,
 	return nil, nil
 }
 
+/* rcmd 의 record 위치, file:line:column
+ * 파일에서 parsing 되지 않은 rcmd 는 ""
+ */
+func GetRcmdPosition(rcmd RcmdInterface) string {
+	value := reflect.ValueOf(rcmd)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return ""
+	}
+
+	field := value.Elem().FieldByName("Pos")
+	if !field.IsValid() {
+		return ""
+	}
+
+	pos, ok := field.Interface().(lexer.Position)
+	if !ok {
+		return ""
+	}
+	return GetPositionString(pos)
+}
+
+func GetPositionString(pos lexer.Position) string {
+	if len(pos.Filename) == 0 {
+		return ""
+	}
+	return pos.String()
+}
+
 /* Rcmd struct 는 parsing 조건에 따라 하나의 rcmd 만 nil 이 아님
  * 각 field에서 nil이 아닌 개별 rcmd를 찾아 Rcmd Interface 를 return 함
  */
@@ -353,6 +436,8 @@ func FieldValueToRcmdInterface(fieldValue interface{}) (RcmdInterface, *errors.E
 		obj = fieldValue.(*BP)
 	case *Break:
 		obj = fieldValue.(*Break)
+	case *Call:
+		obj = fieldValue.(*Call)
 	case *Check:
 		obj = fieldValue.(*Check)
 	case *Close:
@@ -375,22 +460,38 @@ func FieldValueToRcmdInterface(fieldValue interface{}) (RcmdInterface, *errors.E
 		obj = fieldValue.(*Error)
 	case *Expect:
 		obj = fieldValue.(*Expect)
+	case *ExpectBlock:
+		obj = fieldValue.(*ExpectBlock)
+	case *ExpectScreen:
+		obj = fieldValue.(*ExpectScreen)
 	case *For:
 		obj = fieldValue.(*For)
 	case *Get:
 		obj = fieldValue.(*Get)
 	case *If:
 		obj = fieldValue.(*If)
+	case *Import:
+		obj = fieldValue.(*Import)
+	case *Include:
+		obj = fieldValue.(*Include)
 	case *Load:
 		obj = fieldValue.(*Load)
 	case *Log:
 		obj = fieldValue.(*Log)
+	case *Monitor:
+		obj = fieldValue.(*Monitor)
+	case *Parallel:
+		obj = fieldValue.(*Parallel)
+	case *Proc:
+		obj = fieldValue.(*Proc)
 	case *Put:
 		obj = fieldValue.(*Put)
 	case *Return:
 		obj = fieldValue.(*Return)
 	case *Require:
 		obj = fieldValue.(*Require)
+	case *Rest:
+		obj = fieldValue.(*Rest)
 	case *Script:
 		obj = fieldValue.(*Script)
 	case *Send:
@@ -405,12 +506,18 @@ func FieldValueToRcmdInterface(fieldValue interface{}) (RcmdInterface, *errors.E
 		obj = fieldValue.(*Spawn)
 	case *Table:
 		obj = fieldValue.(*Table)
+	case *Try:
+		obj = fieldValue.(*Try)
 	case *Unload:
 		obj = fieldValue.(*Unload)
 	case *Unset:
 		obj = fieldValue.(*Unset)
+	case *Until:
+		obj = fieldValue.(*Until)
 	case *Version:
 		obj = fieldValue.(*Version)
+	case *While:
+		obj = fieldValue.(*While)
 	default:
 		return nil, errors.New(fmt.Sprintf("%s, invalid rcmd", reflect.TypeOf(fieldValue).String()))
 	}
@@ -494,6 +601,33 @@ func SpecRcmd(rcmd RcmdInterface) *errors.Error {
 		if err != nil {
 			return err
 		}
+	case *Parallel:
+		for _, branch := range rcmd.(*Parallel).BranchList {
+			err := Spec(branch.RcmdList)
+			if err != nil {
+				return err
+			}
+		}
+	case *Proc:
+		err := Spec(rcmd.(*Proc).RcmdList)
+		if err != nil {
+			return err
+		}
+	case *Try:
+		err := Spec(rcmd.(*Try).RcmdList)
+		if err != nil {
+			return err
+		}
+	case *Until:
+		err := Spec(rcmd.(*Until).RcmdList)
+		if err != nil {
+			return err
+		}
+	case *While:
+		err := Spec(rcmd.(*While).RcmdList)
+		if err != nil {
+			return err
+		}
 	case *Comment:
 		commentRcmd := rcmd.(*Comment)
 		comment := strings.TrimSpace(commentRcmd.Name[1:])
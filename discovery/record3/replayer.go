@@ -11,12 +11,13 @@ import (
 )
 
 type Replayer struct {
-	TestName  string
-	SetName   string
-	SetType   string
-	TimeStamp string
-	LogDir    string
-	Set       *ReplaySet
+	TestName   string
+	SetName    string
+	SetType    string
+	TimeStamp  string
+	LogDir     string
+	ReportList []string
+	Set        *ReplaySet
 }
 
 /* replay set을 생성한다.
@@ -87,14 +88,25 @@ func NewReplayer(arg *ReplayerArg) (*Replayer, *errors.Error) {
 	if err != nil {
 		return nil, err
 	}
+	set.Parallel = arg.Parallel
+	set.LockWait = arg.LockWait
+
+	if arg.Syslog {
+		reporter, err := NewSyslogReporter(arg.TestName)
+		if err != nil {
+			return nil, err
+		}
+		set.Reporter = reporter
+	}
 
 	replayer := Replayer{
-		TestName:  arg.TestName,
-		SetName:   set.Name,
-		SetType:   set.Type,
-		TimeStamp: timestamp,
-		LogDir:    logdir,
-		Set:       set,
+		TestName:   arg.TestName,
+		SetName:    set.Name,
+		SetType:    set.Type,
+		TimeStamp:  timestamp,
+		LogDir:     logdir,
+		ReportList: arg.ReportList,
+		Set:        set,
 	}
 
 	return &replayer, nil
@@ -106,6 +118,10 @@ func (self *Replayer) Play() *errors.Error {
 	}
 	set := self.Set
 
+	if set.Reporter != nil {
+		defer set.Reporter.Close()
+	}
+
 	result, err := NewResult(self.TestName, self.SetName, self.SetType, self.TimeStamp, self.LogDir)
 	if err != nil {
 		return err
@@ -121,9 +137,11 @@ func (self *Replayer) Play() *errors.Error {
 	result.CountResult()
 	result.PrintSummary()
 
-	err = result.WriteJson()
-	if err != nil {
-		return err
+	for _, report := range self.ReportList {
+		err = REPORT_TABLE[report](result)
+		if err != nil {
+			return err
+		}
 	}
 
 	err = result.WriteIncompleteRecordSet()
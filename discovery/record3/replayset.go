@@ -11,6 +11,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 )
 
 type ReplaySet struct {
@@ -22,6 +23,9 @@ type ReplaySet struct {
 	ForceEnvId     string   // override env id
 	NoEnvHashCheck bool     // true인 경우 env hash를 하지 않음
 	Args           []string // replayer arguments 값
+	Parallel       int      // 동시 실행 record 개수
+	LockWait       int      // env lease 대기 시간(초)
+	Reporter       *SyslogReporter
 }
 
 func NewReplaySet(setname string, logdir string,
@@ -236,82 +240,177 @@ func (self *ReplaySet) Play(result *Result) *errors.Error {
 		return errors.New("invalid arguments")
 	}
 
-	/* result 를 화면에 출력할지 여부, depth indent 초기 설정
-	 */
-	printflag := true
-	depthindent := ""
+	if self.Parallel > 1 {
+		return self.playParallel(result)
+	}
 
 	for seq, record := range self.RecordList {
-		rid := utils.Rid(record.Name, record.Category)
-		recordLogDir := fmt.Sprintf("%s/%d", self.LogDir, seq)
+		rcdresult, err := self.playRecord(seq, record, nil)
+		if rcdresult != nil {
+			result.AddRecordResult(rcdresult)
+		}
 
-		/* record result 생성하고, result 에 추가
-		 */
-		rcdresult, err := NewRecordResult(uint32(seq), rid, printflag, depthindent)
 		if err != nil {
 			return err
 		}
-		result.AddRecordResult(rcdresult)
+	}
 
-		context, err := NewReplayerContext(record.Name, record.Category,
-			recordLogDir, false, rcdresult, self.ForceEnvId, self.NoEnvHashCheck, self.Args)
+	return nil
+}
 
-		if err != nil {
-			errorresult, err1 := NewErrorResult(err, nil, printflag, depthindent)
-			if err1 != nil {
-				return err1
-			}
-			/* result 설정 및 summary 출력
-			 */
-			rcdresult.SetResult(errorresult)
-			rcdresult.CountResult()
-			rcdresult.PrintSummary()
+/* Parallel 개수의 worker 에서 record 실행
+ * 같은 env 를 사용하는 record 는 한 worker 에서 순서대로 실행
+ * record 출력은 record 단위로 모아서 출력, result 는 set 순서대로 추가
+ */
+func (self *ReplaySet) playParallel(result *Result) *errors.Error {
+	jobs := [][]int{}
+	envJobIndex := make(map[string]int)
 
-			continue
+	for seq, record := range self.RecordList {
+		envid := record.GetEnvId(self.ForceEnvId)
+		if len(envid) > 0 {
+			if idx, ok := envJobIndex[envid]; ok {
+				jobs[idx] = append(jobs[idx], seq)
+				continue
+			}
+			envJobIndex[envid] = len(jobs)
 		}
+		jobs = append(jobs, []int{seq})
+	}
 
-		err = record.Play(context)
-		if err != nil {
-			errorresult, err1 := NewErrorResult(err, context, printflag, depthindent)
-			if err1 != nil {
-				return err1
+	rcdresults := make([]*RecordResult, len(self.RecordList))
+	jobErrors := make([]*errors.Error, len(jobs))
+
+	jobChan := make(chan int)
+	var wg sync.WaitGroup
+
+	for i := 0; i < self.Parallel; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for idx := range jobChan {
+				for _, seq := range jobs[idx] {
+					printer := fmt.NewBufferPrinter(nil)
+					rcdresult, err := self.playRecord(seq, self.RecordList[seq], printer)
+					printer.Flush()
+
+					/* 이후 출력은 바로 화면 출력
+					 */
+					if rcdresult != nil {
+						rcdresult.SetPrinter(nil)
+					}
+
+					rcdresults[seq] = rcdresult
+					if err != nil {
+						jobErrors[idx] = err
+						break
+					}
+				}
 			}
-			/* result 설정 및 summary 출력
-			 */
-			rcdresult.SetResult(errorresult)
-			rcdresult.CountResult()
-			rcdresult.PrintSummary()
+		}()
+	}
 
-			context.Close()
-			continue
+	for idx := range jobs {
+		jobChan <- idx
+	}
+	close(jobChan)
+	wg.Wait()
+
+	for _, rcdresult := range rcdresults {
+		if rcdresult != nil {
+			result.AddRecordResult(rcdresult)
 		}
+	}
 
-		err = record.Checker(context)
+	for _, err := range jobErrors {
 		if err != nil {
-			errorresult, err1 := NewErrorResult(err, context, printflag, depthindent)
-			if err1 != nil {
-				return err1
-			}
-			/* result 설정 및 summary 출력
-			 */
-			rcdresult.SetResult(errorresult)
-			rcdresult.CountResult()
-			rcdresult.PrintSummary()
+			return err
+		}
+	}
 
-			context.Close()
-			continue
+	return nil
+}
+
+/* record 하나 실행, printer 가 nil 이 아니면 record 출력을 printer 에 모음
+ */
+func (self *ReplaySet) playRecord(seq int, record *Record, printer *fmt.Printer) (*RecordResult, *errors.Error) {
+	/* result 를 화면에 출력할지 여부, depth indent 초기 설정
+	 */
+	printflag := true
+	depthindent := ""
+
+	rid := utils.Rid(record.Name, record.Category)
+	recordLogDir := fmt.Sprintf("%s/%d", self.LogDir, seq)
+
+	/* record result 생성
+	 */
+	rcdresult, err := NewRecordResult(uint32(seq), rid, printflag, depthindent)
+	if err != nil {
+		return nil, err
+	}
+
+	if self.Reporter != nil {
+		rcdresult.SetReporter(self.Reporter)
+	}
+	rcdresult.SetPrinter(printer)
+
+	context, err := NewReplayerContext(record.Name, record.Category,
+		recordLogDir, false, rcdresult, self.ForceEnvId, self.NoEnvHashCheck, self.Args)
+
+	if err != nil {
+		errorresult, err1 := NewErrorResult(err, nil, printflag, depthindent)
+		if err1 != nil {
+			return rcdresult, err1
+		}
+		/* result 설정 및 summary 출력
+		 */
+		rcdresult.SetResult(errorresult)
+		rcdresult.CountResult()
+		rcdresult.PrintSummary()
+
+		return rcdresult, nil
+	}
+	defer context.Close()
+	context.LockWait = self.LockWait
+
+	err = record.Play(context)
+	if err != nil {
+		errorresult, err1 := NewErrorResult(err, context, printflag, depthindent)
+		if err1 != nil {
+			return rcdresult, err1
 		}
+		/* result 설정 및 summary 출력
+		 */
+		rcdresult.SetResult(errorresult)
+		rcdresult.CountResult()
+		rcdresult.PrintSummary()
+
+		return rcdresult, nil
+	}
 
+	err = record.Checker(context)
+	if err != nil {
+		errorresult, err1 := NewErrorResult(err, context, printflag, depthindent)
+		if err1 != nil {
+			return rcdresult, err1
+		}
 		/* result 설정 및 summary 출력
 		 */
-		rcdresult.SetResult(nil)
+		rcdresult.SetResult(errorresult)
 		rcdresult.CountResult()
 		rcdresult.PrintSummary()
 
-		context.Close()
+		return rcdresult, nil
 	}
 
-	return nil
+	/* result 설정 및 summary 출력
+	 */
+	rcdresult.SetResult(nil)
+	rcdresult.CountResult()
+	rcdresult.PrintSummary()
+
+	return rcdresult, nil
 }
 
 func (self *ReplaySet) Dump() {
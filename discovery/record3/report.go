@@ -0,0 +1,323 @@
+package record3
+
+import (
+	"discovery/constdef"
+	"discovery/errors"
+	"discovery/fmt"
+	"encoding/xml"
+	"os"
+	"strings"
+)
+
+/* -report 옵션으로 선택하는 result writer
+ * DEFAULT_REPORT(json) 은 report, resultdiff, failset 에서 사용하므로 항상 기록
+ */
+var REPORT_TABLE = map[string]func(*Result) *errors.Error{
+	"html":  (*Result).WriteHtml,
+	"json":  (*Result).WriteJson,
+	"junit": (*Result).WriteJunit,
+	"tap":   (*Result).WriteTap,
+}
+
+var DEFAULT_REPORT string = "json"
+
+func ParseReportList(report string) ([]string, *errors.Error) {
+	reportList := []string{DEFAULT_REPORT}
+	for _, name := range strings.Split(report, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if len(name) == 0 {
+			continue
+		}
+
+		if _, ok := REPORT_TABLE[name]; !ok {
+			return nil, errors.New(fmt.Sprintf("'%s' is invalid report. json, junit, tap, html can available", name))
+		}
+
+		if name == DEFAULT_REPORT {
+			continue
+		}
+		reportList = append(reportList, name)
+	}
+
+	return reportList, nil
+}
+
+/* report 출력 단위, record result 의 step 을 펼친 결과
+ */
+type ReportCase struct {
+	Suite   string // record rid
+	Name    string
+	Status  string // success, fail, error, skip
+	Message string
+	Output  string
+}
+
+/* record result 를 report case 로 변환
+ * require 는 require 된 record 의 step 을 같은 suite 에 추가
+ */
+func (self *RecordResult) GetReportCases(suite string, prefix string) []*ReportCase {
+	cases := []*ReportCase{}
+
+	for _, step := range self.Steps {
+		switch step.GetType() {
+		case "check":
+			checkresult := step.GetCheckResult()
+			if !checkresult.CheckDone {
+				continue
+			}
+
+			name := checkresult.Comment
+			if len(name) == 0 {
+				name = checkresult.CheckCondition
+			}
+
+			reportcase := ReportCase{
+				Suite:  suite,
+				Name:   prefix + name,
+				Output: checkresult.GetReportOutput(),
+			}
+
+			switch checkresult.ResultCode {
+			case constdef.SUCCESS:
+				reportcase.Status = "success"
+			case constdef.FAIL:
+				reportcase.Status = "fail"
+				reportcase.Message = checkresult.CheckCondition
+			default:
+				reportcase.Status = "skip"
+			}
+			cases = append(cases, &reportcase)
+
+		case "checker":
+			checkerresult := step.GetCheckerResult()
+
+			reportcase := ReportCase{
+				Suite:  suite,
+				Name:   prefix + checkerresult.CheckerPath,
+				Output: strings.Join(checkerresult.OutputString, "\n"),
+			}
+
+			switch checkerresult.ResultCode {
+			case constdef.SUCCESS:
+				reportcase.Status = "success"
+			case constdef.FAIL:
+				reportcase.Status = "fail"
+				reportcase.Message = "checker failed"
+			default:
+				reportcase.Status = "skip"
+			}
+			cases = append(cases, &reportcase)
+
+		case "error":
+			cases = append(cases, step.GetErrorResult().GetReportCase(suite, prefix+"error"))
+
+		case "require":
+			requireresult := step.GetRequireResult()
+			cases = append(cases, requireresult.GetReportCases(suite, fmt.Sprintf("%srequire %s: ", prefix, requireresult.Name))...)
+		}
+	}
+
+	if self.ErrorResult != nil && self.ErrorResult.Error != nil {
+		cases = append(cases, self.ErrorResult.GetReportCase(suite, prefix+"record error"))
+	}
+
+	return cases
+}
+
+/* check 의 send, output, exit code 정보
+ */
+func (self *CheckResult) GetReportOutput() string {
+	lines := []string{}
+
+	if len(self.LastSend) > 0 {
+		lines = append(lines, fmt.Sprintf("last send: %s", self.LastSend))
+	}
+
+	if len(self.OutputString) > 0 {
+		lines = append(lines, "output_string:")
+		lines = append(lines, self.OutputString...)
+	}
+
+	if self.ExitCode != -1 {
+		lines = append(lines, fmt.Sprintf("exit_code: %d", self.ExitCode))
+	}
+
+	if len(self.CheckCondition) > 0 {
+		lines = append(lines, fmt.Sprintf("check condition: %s", self.CheckCondition))
+	}
+
+	if len(self.Position) > 0 {
+		lines = append(lines, fmt.Sprintf("position: %s", self.Position))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+/* context dump 는 화면 출력과 같이 DumpContext 인 경우만 추가
+ */
+func (self *ErrorResult) GetReportCase(suite string, name string) *ReportCase {
+	reportcase := ReportCase{
+		Suite:   suite,
+		Name:    name,
+		Status:  "error",
+		Message: self.Error.ToString(false),
+	}
+
+	if DumpContext {
+		reportcase.Output = self.ContextDump
+	}
+
+	return &reportcase
+}
+
+func (self *Result) writeReportFile(name string, filename string, data []byte) *errors.Error {
+	path := fmt.Sprintf("%s/%s", self.LogDir, filename)
+
+	goerr := os.WriteFile(path, data, 0644)
+	if goerr != nil {
+		return errors.New(fmt.Sprintf("%s", goerr))
+	}
+
+	fmt.Printf("* Result %s path: %s\n\n", name, path)
+
+	return nil
+}
+
+/* junit xml
+ * record result 는 testsuite, check, checker 는 testcase
+ */
+type junitTestSuites struct {
+	XMLName    xml.Name          `xml:"testsuites"`
+	Name       string            `xml:"name,attr"`
+	Tests      int               `xml:"tests,attr"`
+	Failures   int               `xml:"failures,attr"`
+	Errors     int               `xml:"errors,attr"`
+	TestSuites []*junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string           `xml:"name,attr"`
+	Id        uint32           `xml:"id,attr"`
+	Tests     int              `xml:"tests,attr"`
+	Failures  int              `xml:"failures,attr"`
+	Errors    int              `xml:"errors,attr"`
+	Skipped   int              `xml:"skipped,attr"`
+	Time      string           `xml:"time,attr"`
+	Timestamp string           `xml:"timestamp,attr"`
+	TestCases []*junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut *junitMessage `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr,omitempty"`
+	Text    string `xml:",cdata"`
+}
+
+func (self *Result) WriteJunit() *errors.Error {
+	suites := junitTestSuites{
+		Name:       self.Testname,
+		TestSuites: []*junitTestSuite{},
+	}
+
+	for _, rcdresult := range self.RecordResults {
+		suite := junitTestSuite{
+			Name:      rcdresult.Name,
+			Id:        rcdresult.Seq,
+			Time:      fmt.Sprintf("%.3f", float64(rcdresult.RunTime)/1000),
+			Timestamp: rcdresult.StartTime.Format("2006-01-02T15:04:05"),
+			TestCases: []*junitTestCase{},
+		}
+
+		for _, reportcase := range rcdresult.GetReportCases(rcdresult.Name, "") {
+			testcase := junitTestCase{
+				Name:      reportcase.Name,
+				Classname: reportcase.Suite,
+			}
+
+			if len(reportcase.Output) > 0 {
+				testcase.SystemOut = &junitMessage{Text: reportcase.Output}
+			}
+
+			switch reportcase.Status {
+			case "fail":
+				testcase.Failure = &junitMessage{Message: reportcase.Message, Text: reportcase.Message}
+				suite.Failures++
+			case "error":
+				testcase.Error = &junitMessage{Message: strings.SplitN(reportcase.Message, "\n", 2)[0], Text: reportcase.Message}
+				suite.Errors++
+			case "skip":
+				testcase.Skipped = &junitMessage{}
+				suite.Skipped++
+			}
+
+			suite.TestCases = append(suite.TestCases, &testcase)
+		}
+		suite.Tests = len(suite.TestCases)
+
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Errors += suite.Errors
+		suites.TestSuites = append(suites.TestSuites, &suite)
+	}
+
+	data, goerr := xml.MarshalIndent(&suites, "", "  ")
+	if goerr != nil {
+		return errors.New(fmt.Sprintf("%s", goerr))
+	}
+
+	return self.writeReportFile("junit", "results.junit.xml", append([]byte(xml.Header), data...))
+}
+
+/* TAP version 13
+ * fail, error 는 yaml block 에 message, output 추가
+ */
+func (self *Result) WriteTap() *errors.Error {
+	cases := []*ReportCase{}
+	for _, rcdresult := range self.RecordResults {
+		cases = append(cases, rcdresult.GetReportCases(rcdresult.Name, "")...)
+	}
+
+	lines := []string{"TAP version 13", fmt.Sprintf("1..%d", len(cases))}
+
+	for i, reportcase := range cases {
+		description := strings.ReplaceAll(fmt.Sprintf("%s: %s", reportcase.Suite, reportcase.Name), "#", "\\#")
+
+		switch reportcase.Status {
+		case "success":
+			lines = append(lines, fmt.Sprintf("ok %d - %s", i+1, description))
+			continue
+		case "skip":
+			lines = append(lines, fmt.Sprintf("ok %d - %s # SKIP", i+1, description))
+			continue
+		}
+
+		lines = append(lines, fmt.Sprintf("not ok %d - %s", i+1, description))
+		lines = append(lines, "  ---")
+		lines = append(lines, fmt.Sprintf("  severity: %s", reportcase.Status))
+		lines = append(lines, tapYamlBlock("message", reportcase.Message)...)
+		lines = append(lines, tapYamlBlock("output", reportcase.Output)...)
+		lines = append(lines, "  ...")
+	}
+
+	return self.writeReportFile("tap", "results.tap", []byte(strings.Join(lines, "\n")+"\n"))
+}
+
+func tapYamlBlock(key string, value string) []string {
+	if len(value) == 0 {
+		return []string{}
+	}
+
+	lines := []string{fmt.Sprintf("  %s: |", key)}
+	for _, line := range strings.Split(value, "\n") {
+		lines = append(lines, "    "+line)
+	}
+	return lines
+}
@@ -62,7 +62,7 @@ func (self *Result) AddRecordResult(recordResult *RecordResult) {
 }
 
 func (self *Result) PrintSummary() {
-	fmt.Println("\n")
+	fmt.Printf("\n\n")
 	fmt.Printf("%sㅁ 총 실행 레코드: %d%s\n", constdef.ANSI_GREEN, self.Recordcount, constdef.ANSI_END)
 	fmt.Printf("%sㅁ 성공 스텝 개수: %d%s\n", constdef.ANSI_GREEN, self.Successcount, constdef.ANSI_END)
 	fmt.Printf("%sㅁ 실패 스텝 개수: %d%s\n", constdef.ANSI_GREEN, self.Failcount, constdef.ANSI_END)
@@ -172,6 +172,9 @@ type RecordResult struct {
 	Successcount uint32
 	Failcount    uint32
 	Errorcount   uint32
+
+	Reporter *SyslogReporter `json:"-"` // 진행 상황 syslog 전송, nil 이면 전송 안함
+	Printer  *fmt.Printer    `json:"-"` // 결과 출력 buffer, nil 이면 바로 화면 출력
 }
 
 func NewRecordResult(seq uint32, name string, printflag bool, depthindent string) (*RecordResult, *errors.Error) {
@@ -212,24 +215,49 @@ func (self *RecordResult) GetResult() (uint32, uint32, uint32) {
 func (self *RecordResult) PrintTitle() {
 	if self.PrintFlag {
 		// 화면 print
-		fmt.Println("\n")
-		fmt.Printf("%s%s%05d, 레코드 \"%s\"%s", self.DepthIndent, constdef.ANSI_CYAN, self.Seq, self.Name, constdef.ANSI_END)
+		self.Printer.Printf("\n\n")
+		self.Printer.Printf("%s%s%05d, 레코드 \"%s\"%s", self.DepthIndent, constdef.ANSI_CYAN, self.Seq, self.Name, constdef.ANSI_END)
 	}
 }
 
 func (self *RecordResult) PrintSummary() {
 	if self.PrintFlag {
-		fmt.Println()
-		fmt.Printf("%s%s성공 스텝: %d%s\n", self.DepthIndent, constdef.ANSI_CYAN, self.Successcount, constdef.ANSI_END)
-		fmt.Printf("%s%s실패 스텝: %d%s\n", self.DepthIndent, constdef.ANSI_CYAN, self.Failcount, constdef.ANSI_END)
-		fmt.Printf("%s%s     에러: %d%s\n", self.DepthIndent, constdef.ANSI_CYAN, self.Errorcount, constdef.ANSI_END)
-		fmt.Printf("%s%s실행 시간: %dms%s\n", self.DepthIndent, constdef.ANSI_CYAN, self.RunTime, constdef.ANSI_END)
+		self.Printer.Println()
+		self.Printer.Printf("%s%s성공 스텝: %d%s\n", self.DepthIndent, constdef.ANSI_CYAN, self.Successcount, constdef.ANSI_END)
+		self.Printer.Printf("%s%s실패 스텝: %d%s\n", self.DepthIndent, constdef.ANSI_CYAN, self.Failcount, constdef.ANSI_END)
+		self.Printer.Printf("%s%s     에러: %d%s\n", self.DepthIndent, constdef.ANSI_CYAN, self.Errorcount, constdef.ANSI_END)
+		self.Printer.Printf("%s%s실행 시간: %dms%s\n", self.DepthIndent, constdef.ANSI_CYAN, self.RunTime, constdef.ANSI_END)
 	}
 }
 
+/* reporter 설정하고 record 시작 전송
+ */
+func (self *RecordResult) SetReporter(reporter *SyslogReporter) {
+	self.Reporter = reporter
+	self.reportStart()
+}
+
+func (self *RecordResult) SetPrinter(printer *fmt.Printer) {
+	self.Printer = printer
+}
+
 func (self *RecordResult) AddStep(step *Step) {
 	self.Steps = append(self.Steps, step)
 
+	/* check, checker 결과 설정시 전송, 출력하기 위해 record result 연결
+	 */
+	switch result := step.Result.(type) {
+	case *CheckResult:
+		result.rcdresult = self
+		result.printer = self.Printer
+	case *CheckerResult:
+		result.rcdresult = self
+		result.printer = self.Printer
+	case *ErrorResult:
+		result.printer = self.Printer
+		self.reportError(result)
+	}
+
 	if self.PrintFlag {
 		step.PrintMsg()
 	}
@@ -244,7 +272,7 @@ func (self *RecordResult) GetLastCheckStep() *CheckResult {
 	switch lastStep.GetType() {
 	case "check":
 		chkresult := lastStep.Result.(*CheckResult)
-		if chkresult.CheckDone || chkresult.CommentType != "*" {
+		if chkresult.CheckDone || chkresult.claimed || chkresult.CommentType != "*" {
 			return nil
 		}
 		return chkresult
@@ -257,27 +285,34 @@ func (self *RecordResult) SetResult(errorresult *ErrorResult) {
 	self.RunTime = time.Now().Sub(self.StartTime) / time.Millisecond
 	self.ErrorResult = errorresult
 
+	self.reportError(errorresult)
+	self.reportFinish()
+
 	if self.PrintFlag {
 		// 화면 print
 		if errorresult != nil {
-			fmt.Printf("\n%s%s\"%s\" 레코드 재생 중 에러가 발생했습니다.%s\n", self.DepthIndent, constdef.ANSI_YELLOW2, self.Name, constdef.ANSI_END)
+			self.Printer.Printf("\n%s%s\"%s\" 레코드 재생 중 에러가 발생했습니다.%s\n", self.DepthIndent, constdef.ANSI_YELLOW2, self.Name, constdef.ANSI_END)
+
+			if len(errorresult.Error.Position) > 0 {
+				self.Printer.Printf("%s- Position: %s\n", self.DepthIndent, errorresult.Error.Position)
+			}
 
-			fmt.Printf("%s- Error message:\n", self.DepthIndent)
+			self.Printer.Printf("%s- Error message:\n", self.DepthIndent)
 			arr := strings.Split(errorresult.Error.Msg, "\n")
 			for i, msg := range arr {
-				fmt.Printf("%s| %s", self.DepthIndent, msg)
+				self.Printer.Printf("%s| %s", self.DepthIndent, msg)
 				if i < len(arr)-1 {
-					fmt.Println()
+					self.Printer.Println()
 				}
 			}
 
 			if DumpContext {
-				fmt.Printf("%s- Context dump message:\n", self.DepthIndent)
+				self.Printer.Printf("%s- Context dump message:\n", self.DepthIndent)
 				arr = strings.Split(errorresult.ContextDump, "\n")
 				for i, msg := range arr {
-					fmt.Printf("%s| %s", self.DepthIndent, msg)
+					self.Printer.Printf("%s| %s", self.DepthIndent, msg)
 					if i < len(arr)-1 {
-						fmt.Println()
+						self.Printer.Println()
 					}
 				}
 			}
@@ -413,7 +448,12 @@ type CheckResult struct {
 	OutputString        []string // expect 후 output string
 	ExitCode            int32    // exit code 값
 	CheckCondition      string   // check condition 문자열
+	Position            string   `json:",omitempty"` // check rcmd 의 record 위치, file:line:column
 	ResultCode          uint8    // check 결과 값
+
+	rcdresult *RecordResult
+	printer   *fmt.Printer
+	claimed   bool // until 등 body 수행 후 결과를 기록할 rcmd 가 선점한 comment step
 }
 
 func NewCheckResult(commentType, comment string, printflag bool, depthindent string) (*CheckResult, *errors.Error) {
@@ -442,22 +482,22 @@ func (self *CheckResult) PrintMsg() {
 	if self.PrintFlag {
 		switch self.CommentType {
 		case "=":
-			fmt.Println()
-			fmt.Printf("%s %s# %s%s", self.DepthIndent, constdef.ANSI_CYAN_BOLD, self.Comment, constdef.ANSI_END)
+			self.printer.Println()
+			self.printer.Printf("%s %s# %s%s", self.DepthIndent, constdef.ANSI_CYAN_BOLD, self.Comment, constdef.ANSI_END)
 		case "-":
-			fmt.Println()
-			fmt.Printf("%s %s## %s%s", self.DepthIndent, constdef.ANSI_YELLOW_BOLD, self.Comment, constdef.ANSI_END)
+			self.printer.Println()
+			self.printer.Printf("%s %s## %s%s", self.DepthIndent, constdef.ANSI_YELLOW_BOLD, self.Comment, constdef.ANSI_END)
 		case "%":
-			fmt.Println()
-			fmt.Printf("%s %s### %s%s", self.DepthIndent, constdef.ANSI_GREEN, self.Comment, constdef.ANSI_END)
+			self.printer.Println()
+			self.printer.Printf("%s %s### %s%s", self.DepthIndent, constdef.ANSI_GREEN, self.Comment, constdef.ANSI_END)
 		case "#":
-			fmt.Println()
-			fmt.Printf("%s %s  |%s%s", self.DepthIndent, constdef.ANSI_WHITE, self.Comment, constdef.ANSI_END)
+			self.printer.Println()
+			self.printer.Printf("%s %s  |%s%s", self.DepthIndent, constdef.ANSI_WHITE, self.Comment, constdef.ANSI_END)
 		case "*":
-			fmt.Println()
-			fmt.Printf("%s %s[*] %s%s", self.DepthIndent, constdef.ANSI_YELLOW, self.Comment, constdef.ANSI_END)
+			self.printer.Println()
+			self.printer.Printf("%s %s[*] %s%s", self.DepthIndent, constdef.ANSI_YELLOW, self.Comment, constdef.ANSI_END)
 		case "_":
-			fmt.Println()
+			self.printer.Println()
 		}
 	}
 }
@@ -467,7 +507,7 @@ func (self *CheckResult) SetResult(resultCode uint8) {
 	self.ResultCode = resultCode
 
 	if self.PrintFlag {
-		utils.PrintResult(self.ResultCode)
+		self.printer.Printf("%s", utils.ResultString(self.ResultCode))
 	}
 }
 
@@ -481,25 +521,39 @@ func (self *CheckResult) SetInfo(lastsendsessionname string, lastsend string,
 	self.ExitCode = exitcode
 	self.CheckCondition = checkcondition
 
+	/* SetResult 후 SetInfo 가 호출되므로 check condition 이 채워진 뒤 전송
+	 */
+	if self.rcdresult != nil && self.CheckDone {
+		name := self.Comment
+		if len(strings.TrimSpace(name)) == 0 {
+			name = self.CheckCondition
+		}
+		self.rcdresult.reportStep(name, self.ResultCode)
+	}
+
 	if self.PrintFlag {
-		fmt.Println("")
+		self.printer.Println("")
 		if len(self.LastSend) > 0 {
-			fmt.Printf("%s     - last send: %s\n", self.DepthIndent, self.LastSend)
+			self.printer.Printf("%s     - last send: %s\n", self.DepthIndent, self.LastSend)
 		}
 
 		if len(self.OutputString) > 0 {
-			fmt.Printf("%s     - output_string:\n", self.DepthIndent)
+			self.printer.Printf("%s     - output_string:\n", self.DepthIndent)
 			for _, msg := range self.OutputString {
-				fmt.Printf("%s     |%s\n", self.DepthIndent, msg)
+				self.printer.Printf("%s     |%s\n", self.DepthIndent, msg)
 			}
 		}
 
 		if self.ExitCode != -1 {
-			fmt.Printf("%s     - exit_code: %d\n", self.DepthIndent, self.ExitCode)
+			self.printer.Printf("%s     - exit_code: %d\n", self.DepthIndent, self.ExitCode)
+		}
+
+		if len(self.Position) > 0 {
+			self.printer.Printf("%s     - position: %s\n", self.DepthIndent, self.Position)
 		}
 
 		if len(self.CheckCondition) > 0 {
-			fmt.Printf("%s     - check condition: %s", self.DepthIndent, self.CheckCondition)
+			self.printer.Printf("%s     - check condition: %s", self.DepthIndent, self.CheckCondition)
 		}
 	}
 }
@@ -513,6 +567,9 @@ type CheckerResult struct {
 	CheckerPath  string
 	OutputString []string
 	ResultCode   uint8
+
+	rcdresult *RecordResult
+	printer   *fmt.Printer
 }
 
 func NewCheckerResult(checkerpath string, printflag bool, depthindent string) (*CheckerResult, *errors.Error) {
@@ -532,8 +589,8 @@ func NewCheckerResult(checkerpath string, printflag bool, depthindent string) (*
 
 func (self *CheckerResult) PrintMsg() {
 	if self.PrintFlag {
-		fmt.Println()
-		fmt.Printf("%s %s[c] %s%s", self.DepthIndent, constdef.ANSI_YELLOW, self.CheckerPath, constdef.ANSI_END)
+		self.printer.Println()
+		self.printer.Printf("%s %s[c] %s%s", self.DepthIndent, constdef.ANSI_YELLOW, self.CheckerPath, constdef.ANSI_END)
 	}
 }
 
@@ -541,15 +598,19 @@ func (self *CheckerResult) SetResult(output []string, resultcode uint8) {
 	self.OutputString = output
 	self.ResultCode = resultcode
 
+	if self.rcdresult != nil {
+		self.rcdresult.reportStep(self.CheckerPath, resultcode)
+	}
+
 	if self.PrintFlag {
-		utils.PrintResult(resultcode)
+		self.printer.Printf("%s", utils.ResultString(resultcode))
 
-		fmt.Println("")
-		fmt.Printf("%s     - output:\n", self.DepthIndent)
+		self.printer.Println("")
+		self.printer.Printf("%s     - output:\n", self.DepthIndent)
 		for i, msg := range self.OutputString {
-			fmt.Printf("%s     |%s", self.DepthIndent, msg)
+			self.printer.Printf("%s     |%s", self.DepthIndent, msg)
 			if i < len(self.OutputString)-1 {
-				fmt.Println()
+				self.printer.Println()
 			}
 		}
 	}
@@ -563,6 +624,8 @@ type ErrorResult struct {
 
 	Error       *errors.Error
 	ContextDump string
+
+	printer *fmt.Printer
 }
 
 func NewErrorResult(err *errors.Error, context *ReplayerContext, printflag bool, depthindent string) (*ErrorResult, *errors.Error) {
@@ -587,24 +650,28 @@ func (self *ErrorResult) PrintMsg() {
 	if self.PrintFlag {
 		// 화면 print
 		if self.Error != nil {
-			fmt.Printf("\n%s %s[e] RCMD 재생 중 에러가 발생했습니다.%s\n", self.DepthIndent, constdef.ANSI_YELLOW2, constdef.ANSI_END)
+			self.printer.Printf("\n%s %s[e] RCMD 재생 중 에러가 발생했습니다.%s\n", self.DepthIndent, constdef.ANSI_YELLOW2, constdef.ANSI_END)
+
+			if len(self.Error.Position) > 0 {
+				self.printer.Printf("%s     - Position: %s\n", self.DepthIndent, self.Error.Position)
+			}
 
-			fmt.Printf("%s     - Error message:\n", self.DepthIndent)
+			self.printer.Printf("%s     - Error message:\n", self.DepthIndent)
 			arr := strings.Split(self.Error.Msg, "\n")
 			for i, msg := range arr {
-				fmt.Printf("%s     | %s", self.DepthIndent, msg)
+				self.printer.Printf("%s     | %s", self.DepthIndent, msg)
 				if i < len(arr)-1 {
-					fmt.Println()
+					self.printer.Println()
 				}
 			}
 
 			if DumpContext {
-				fmt.Printf("%s     - Context dump message:\n", self.DepthIndent)
+				self.printer.Printf("%s     - Context dump message:\n", self.DepthIndent)
 				arr = strings.Split(self.ContextDump, "\n")
 				for i, msg := range arr {
-					fmt.Printf("%s     | %s", self.DepthIndent, msg)
+					self.printer.Printf("%s     | %s", self.DepthIndent, msg)
 					if i < len(arr)-1 {
-						fmt.Println()
+						self.printer.Println()
 					}
 				}
 			}
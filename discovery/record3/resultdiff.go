@@ -0,0 +1,257 @@
+package record3
+
+import (
+	"discovery/constdef"
+	"discovery/errors"
+	"discovery/fmt"
+)
+
+/* result diff 항목 종류
+ */
+const (
+	DIFF_NEWLY_FAILING = "newly failing"
+	DIFF_NEWLY_PASSING = "newly passing"
+	DIFF_DISAPPEARED   = "disappeared"
+	DIFF_RUNTIME       = "runtime"
+)
+
+/* 두 result 비교 결과
+ * record 는 rid, step 은 comment 또는 check condition 으로 매칭
+ */
+type ResultDiff struct {
+	OldResult *Result
+	NewResult *Result
+
+	RuntimeThreshold float64 // run time 변화 비율(%), 이 값 이상이면 runtime 항목 추가
+	RuntimeMinimum   int64   // run time 변화 최소값(ms), 작은 record 의 변화는 무시
+
+	Items []*ResultDiffItem
+}
+
+type ResultDiffItem struct {
+	Kind       string
+	Rid        string
+	Step       string // record 전체인 경우 ""
+	OldStatus  string
+	NewStatus  string
+	OldRunTime int64
+	NewRunTime int64
+}
+
+func NewResultDiff(oldResult, newResult *Result, runtimeThreshold float64, runtimeMinimum int64) (*ResultDiff, *errors.Error) {
+	if oldResult == nil || newResult == nil {
+		return nil, errors.New("invalid arguments")
+	}
+
+	diff := ResultDiff{
+		OldResult:        oldResult,
+		NewResult:        newResult,
+		RuntimeThreshold: runtimeThreshold,
+		RuntimeMinimum:   runtimeMinimum,
+		Items:            []*ResultDiffItem{},
+	}
+	diff.compare()
+
+	return &diff, nil
+}
+
+/* 같은 이름이 여러번 나오면 순서 번호를 붙여 구분
+ */
+func getDiffKeys(names []string) []string {
+	keys := []string{}
+	countMap := make(map[string]int)
+	for _, name := range names {
+		key := name
+		if count := countMap[name]; count > 0 {
+			key = fmt.Sprintf("%s #%d", name, count+1)
+		}
+		countMap[name] += 1
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func getRecordResultMap(result *Result) ([]string, map[string]*RecordResult) {
+	names := []string{}
+	for _, rcdresult := range result.RecordResults {
+		names = append(names, rcdresult.Name)
+	}
+
+	keys := getDiffKeys(names)
+	rcdresultMap := make(map[string]*RecordResult)
+	for i, key := range keys {
+		rcdresultMap[key] = result.RecordResults[i]
+	}
+	return keys, rcdresultMap
+}
+
+func getReportCaseMap(rcdresult *RecordResult) ([]string, map[string]*ReportCase) {
+	cases := rcdresult.GetReportCases(rcdresult.Name, "")
+
+	names := []string{}
+	for _, reportcase := range cases {
+		names = append(names, reportcase.Name)
+	}
+
+	keys := getDiffKeys(names)
+	caseMap := make(map[string]*ReportCase)
+	for i, key := range keys {
+		caseMap[key] = cases[i]
+	}
+	return keys, caseMap
+}
+
+func isFailStatus(status string) bool {
+	return status == "fail" || status == "error"
+}
+
+func (self *ResultDiff) compare() {
+	oldKeys, oldMap := getRecordResultMap(self.OldResult)
+	newKeys, newMap := getRecordResultMap(self.NewResult)
+
+	for _, rid := range newKeys {
+		newRcdresult := newMap[rid]
+		oldRcdresult, ok := oldMap[rid]
+		if !ok {
+			oldRcdresult = nil
+		}
+		self.compareRecord(rid, oldRcdresult, newRcdresult)
+	}
+
+	for _, rid := range oldKeys {
+		if _, ok := newMap[rid]; !ok {
+			self.Items = append(self.Items, &ResultDiffItem{
+				Kind:       DIFF_DISAPPEARED,
+				Rid:        rid,
+				OldStatus:  getRecordStatus(oldMap[rid]),
+				OldRunTime: int64(oldMap[rid].RunTime),
+			})
+		}
+	}
+}
+
+func (self *ResultDiff) compareRecord(rid string, oldRcdresult, newRcdresult *RecordResult) {
+	oldCaseMap := make(map[string]*ReportCase)
+	oldCaseKeys := []string{}
+	if oldRcdresult != nil {
+		oldCaseKeys, oldCaseMap = getReportCaseMap(oldRcdresult)
+	}
+	newCaseKeys, newCaseMap := getReportCaseMap(newRcdresult)
+
+	for _, step := range newCaseKeys {
+		newStatus := newCaseMap[step].Status
+		oldStatus := ""
+		if oldCase, ok := oldCaseMap[step]; ok {
+			oldStatus = oldCase.Status
+		}
+
+		item := ResultDiffItem{
+			Rid:       rid,
+			Step:      step,
+			OldStatus: oldStatus,
+			NewStatus: newStatus,
+		}
+
+		if isFailStatus(newStatus) && !isFailStatus(oldStatus) {
+			item.Kind = DIFF_NEWLY_FAILING
+		} else if isFailStatus(oldStatus) && !isFailStatus(newStatus) {
+			item.Kind = DIFF_NEWLY_PASSING
+		} else {
+			continue
+		}
+		self.Items = append(self.Items, &item)
+	}
+
+	for _, step := range oldCaseKeys {
+		if _, ok := newCaseMap[step]; !ok {
+			self.Items = append(self.Items, &ResultDiffItem{
+				Kind:      DIFF_DISAPPEARED,
+				Rid:       rid,
+				Step:      step,
+				OldStatus: oldCaseMap[step].Status,
+			})
+		}
+	}
+
+	if oldRcdresult == nil || self.RuntimeThreshold <= 0 {
+		return
+	}
+
+	oldRunTime := int64(oldRcdresult.RunTime)
+	newRunTime := int64(newRcdresult.RunTime)
+	delta := newRunTime - oldRunTime
+	if delta < 0 {
+		delta = -delta
+	}
+
+	if oldRunTime > 0 && delta >= self.RuntimeMinimum &&
+		float64(delta)*100/float64(oldRunTime) >= self.RuntimeThreshold {
+		self.Items = append(self.Items, &ResultDiffItem{
+			Kind:       DIFF_RUNTIME,
+			Rid:        rid,
+			OldRunTime: oldRunTime,
+			NewRunTime: newRunTime,
+		})
+	}
+}
+
+func getRecordStatus(rcdresult *RecordResult) string {
+	if rcdresult.Errorcount > 0 {
+		return "error"
+	} else if rcdresult.Failcount > 0 {
+		return "fail"
+	}
+	return "success"
+}
+
+func (self *ResultDiff) GetItems(kind string) []*ResultDiffItem {
+	items := []*ResultDiffItem{}
+	for _, item := range self.Items {
+		if item.Kind == kind {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+/* 새로 실패한 step 이 있으면 regression
+ */
+func (self *ResultDiff) HasRegression() bool {
+	return len(self.GetItems(DIFF_NEWLY_FAILING)) > 0
+}
+
+func (self *ResultDiffItem) ToString() string {
+	name := self.Rid
+	if len(self.Step) > 0 {
+		name = fmt.Sprintf("%s: %s", self.Rid, self.Step)
+	}
+
+	switch self.Kind {
+	case DIFF_RUNTIME:
+		return fmt.Sprintf("%s, %dms -> %dms", name, self.OldRunTime, self.NewRunTime)
+	case DIFF_DISAPPEARED:
+		return fmt.Sprintf("%s, %s", name, self.OldStatus)
+	default:
+		oldStatus := self.OldStatus
+		if len(oldStatus) == 0 {
+			oldStatus = "none"
+		}
+		return fmt.Sprintf("%s, %s -> %s", name, oldStatus, self.NewStatus)
+	}
+}
+
+func (self *ResultDiff) Print() {
+	fmt.Printf("%sㅁ old: %s, %s(%s)%s\n", constdef.ANSI_GREEN, self.OldResult.Timestamp, self.OldResult.Testname, self.OldResult.Setname, constdef.ANSI_END)
+	fmt.Printf("%sㅁ new: %s, %s(%s)%s\n", constdef.ANSI_GREEN, self.NewResult.Timestamp, self.NewResult.Testname, self.NewResult.Setname, constdef.ANSI_END)
+
+	for _, kind := range []string{DIFF_NEWLY_FAILING, DIFF_NEWLY_PASSING, DIFF_DISAPPEARED, DIFF_RUNTIME} {
+		items := self.GetItems(kind)
+
+		fmt.Println()
+		fmt.Printf("%s%s: %d%s\n", constdef.ANSI_CYAN, kind, len(items), constdef.ANSI_END)
+		for _, item := range items {
+			fmt.Printf("  %s\n", item.ToString())
+		}
+	}
+	fmt.Println()
+}
@@ -16,6 +16,8 @@ type SessionNode struct {
 	LogPath      string
 	Logger       *ReplayerLogger
 	LogSendCount uint /* 로그 prefix 처리에 사용 */
+
+	MonitorVarName string // monitor start 에서 지정한 변수 이름
 }
 
 func NewSessionNode(logdir, sessionname string, procPtr *proc.PtyProcess, node *config.Node) (*SessionNode, *errors.Error) {
@@ -44,6 +46,9 @@ func NewSessionNode(logdir, sessionname string, procPtr *proc.PtyProcess, node *
 
 func (self *SessionNode) Close() {
 	if self.Proc != nil {
+		if self.Proc.Monitor != nil {
+			self.Proc.StopMonitor()
+		}
 		self.Proc.Stop()
 	}
 
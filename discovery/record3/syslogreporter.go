@@ -0,0 +1,215 @@
+package record3
+
+import (
+	"discovery/config"
+	"discovery/constdef"
+	"discovery/errors"
+	"discovery/fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+/* syslog severity
+ */
+const (
+	SYSLOG_SEVERITY_ERR     = 3
+	SYSLOG_SEVERITY_WARNING = 4
+	SYSLOG_SEVERITY_INFO    = 6
+)
+
+/* RFC5424 structured data id, enterprise number 는 문서용 번호 사용
+ */
+var SYSLOG_SD_ID string = "result@32473"
+var SYSLOG_APP_NAME string = "replayer"
+
+/* report server 응답이 없을 때 replay 가 멈추지 않도록 write timeout
+ */
+var SYSLOG_WRITE_TIMEOUT time.Duration = time.Second * 5
+
+/* replay 진행 상황을 report server 에 RFC5424 syslog 로 전송
+ * tcp 는 RFC6587 octet counting 으로 framing
+ */
+type SyslogReporter struct {
+	Server   *config.ReportServer
+	TestName string
+	Hostname string
+
+	conn     net.Conn
+	mutex    sync.Mutex
+	warnFlag bool
+}
+
+func NewSyslogReporter(testname string) (*SyslogReporter, *errors.Error) {
+	server, err := config.NewReportServer()
+	if err != nil {
+		return nil, err
+	}
+
+	conn, goerr := net.DialTimeout(server.Protocol, net.JoinHostPort(server.Ip, strconv.Itoa(server.Port)), time.Second*5)
+	if goerr != nil {
+		return nil, errors.New(fmt.Sprintf("%s", goerr))
+	}
+
+	hostname, goerr := os.Hostname()
+	if goerr != nil || len(hostname) == 0 {
+		hostname = "-"
+	}
+
+	reporter := SyslogReporter{
+		Server:   server,
+		TestName: testname,
+		Hostname: hostname,
+		conn:     conn,
+	}
+
+	return &reporter, nil
+}
+
+/* params 는 name, value 순서
+ */
+func (self *SyslogReporter) Send(severity int, msgid string, params []string, msg string) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	if self.conn == nil {
+		return
+	}
+
+	sd := []string{SYSLOG_SD_ID, fmt.Sprintf(`test="%s"`, escapeSyslogParam(self.TestName))}
+	for i := 0; i+1 < len(params); i += 2 {
+		sd = append(sd, fmt.Sprintf(`%s="%s"`, params[i], escapeSyslogParam(params[i+1])))
+	}
+
+	line := fmt.Sprintf("<%d>1 %s %s %s %d %s [%s] %s",
+		self.Server.Facility*8+severity, time.Now().Format("2006-01-02T15:04:05.000000Z07:00"),
+		self.Hostname, SYSLOG_APP_NAME, os.Getpid(), msgid, strings.Join(sd, " "), msg)
+
+	if self.Server.Protocol == "tcp" {
+		line = fmt.Sprintf("%d %s", len(line), line)
+	}
+
+	self.conn.SetWriteDeadline(time.Now().Add(SYSLOG_WRITE_TIMEOUT))
+	_, goerr := self.conn.Write([]byte(line))
+	if goerr == nil {
+		return
+	}
+
+	if !self.warnFlag {
+		/* 전송 실패해도 replay 는 계속 진행
+		 */
+		fmt.Printf("WARN: report server %s, %s\n", self.conn.RemoteAddr(), goerr)
+		self.warnFlag = true
+	}
+
+	/* tcp 는 일부만 전송되면 framing 이 깨지므로 이후 전송 중단
+	 */
+	if self.Server.Protocol == "tcp" {
+		self.conn.Close()
+		self.conn = nil
+	}
+}
+
+func (self *SyslogReporter) Close() {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	if self.conn != nil {
+		self.conn.Close()
+		self.conn = nil
+	}
+}
+
+/* structured data param value 는 ", \, ] escape
+ */
+func escapeSyslogParam(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	value = strings.ReplaceAll(value, `]`, `\]`)
+	return value
+}
+
+/* syslog msg 는 한줄
+ */
+func getSyslogMsg(msg string) string {
+	return strings.Join(strings.Fields(msg), " ")
+}
+
+/* record result 전송
+ */
+func (self *RecordResult) reportStart() {
+	if self.Reporter == nil {
+		return
+	}
+
+	self.Reporter.Send(SYSLOG_SEVERITY_INFO, "RECORD_START",
+		[]string{"rid", self.Name, "seq", strconv.Itoa(int(self.Seq))},
+		fmt.Sprintf("record %s start", self.Name))
+}
+
+func (self *RecordResult) reportFinish() {
+	if self.Reporter == nil {
+		return
+	}
+
+	success, fail, errorCount := 0, 0, 0
+	for _, reportcase := range self.GetReportCases(self.Name, "") {
+		switch reportcase.Status {
+		case "success":
+			success++
+		case "fail":
+			fail++
+		case "error":
+			errorCount++
+		}
+	}
+
+	status := "success"
+	severity := SYSLOG_SEVERITY_INFO
+	if errorCount > 0 {
+		status = "error"
+		severity = SYSLOG_SEVERITY_ERR
+	} else if fail > 0 {
+		status = "fail"
+		severity = SYSLOG_SEVERITY_WARNING
+	}
+
+	self.Reporter.Send(severity, "RECORD_FINISH",
+		[]string{"rid", self.Name, "seq", strconv.Itoa(int(self.Seq)), "status", status,
+			"success", strconv.Itoa(success), "fail", strconv.Itoa(fail), "error", strconv.Itoa(errorCount),
+			"runtime", strconv.Itoa(int(self.RunTime))},
+		fmt.Sprintf("record %s finish, %s", self.Name, status))
+}
+
+func (self *RecordResult) reportStep(name string, resultCode uint8) {
+	if self.Reporter == nil {
+		return
+	}
+
+	status := "na"
+	severity := SYSLOG_SEVERITY_INFO
+	switch resultCode {
+	case constdef.SUCCESS:
+		status = "success"
+	case constdef.FAIL:
+		status = "fail"
+		severity = SYSLOG_SEVERITY_WARNING
+	}
+
+	self.Reporter.Send(severity, "STEP",
+		[]string{"rid", self.Name, "seq", strconv.Itoa(int(self.Seq)), "status", status},
+		fmt.Sprintf("%s -> %s", getSyslogMsg(name), status))
+}
+
+func (self *RecordResult) reportError(errorresult *ErrorResult) {
+	if self.Reporter == nil || errorresult == nil || errorresult.Error == nil {
+		return
+	}
+
+	self.Reporter.Send(SYSLOG_SEVERITY_ERR, "ERROR",
+		[]string{"rid", self.Name, "seq", strconv.Itoa(int(self.Seq))},
+		getSyslogMsg(errorresult.Error.ToString(false)))
+}
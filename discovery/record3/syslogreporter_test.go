@@ -0,0 +1,157 @@
+package record3
+
+import (
+	"bufio"
+	"discovery/config"
+	"discovery/constdef"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestSyslogReporter(t *testing.T) (*SyslogReporter, net.Conn) {
+	listener, goerr := net.Listen("tcp", "127.0.0.1:0")
+	if goerr != nil {
+		t.Fatal(goerr)
+	}
+	defer listener.Close()
+
+	conn, goerr := net.Dial("tcp", listener.Addr().String())
+	if goerr != nil {
+		t.Fatal(goerr)
+	}
+
+	serverConn, goerr := listener.Accept()
+	if goerr != nil {
+		t.Fatal(goerr)
+	}
+
+	reporter := &SyslogReporter{
+		Server:   &config.ReportServer{Ip: "127.0.0.1", Protocol: "tcp", Facility: 16},
+		TestName: `test "1"`,
+		Hostname: "host",
+		conn:     conn,
+	}
+	t.Cleanup(func() {
+		reporter.Close()
+		serverConn.Close()
+	})
+
+	return reporter, serverConn
+}
+
+/* RFC6587 octet counting, "MSG-LEN SP SYSLOG-MSG"
+ */
+func readOctetFrame(t *testing.T, reader *bufio.Reader) string {
+	length, goerr := reader.ReadString(' ')
+	if goerr != nil {
+		t.Fatal(goerr)
+	}
+
+	size, goerr := strconv.Atoi(strings.TrimSuffix(length, " "))
+	if goerr != nil {
+		t.Fatalf("invalid frame length %q", length)
+	}
+
+	frame := make([]byte, size)
+	if _, goerr := io.ReadFull(reader, frame); goerr != nil {
+		t.Fatal(goerr)
+	}
+	return string(frame)
+}
+
+func TestSyslogOctetCounting(t *testing.T) {
+	reporter, serverConn := newTestSyslogReporter(t)
+
+	messages := []string{"record start", "한글 메시지", "step ] done"}
+	for _, msg := range messages {
+		reporter.Send(SYSLOG_SEVERITY_INFO, "STEP", []string{"rid", `a\b]`}, msg)
+	}
+
+	serverConn.SetReadDeadline(time.Now().Add(time.Second * 5))
+	reader := bufio.NewReader(serverConn)
+	for _, msg := range messages {
+		frame := readOctetFrame(t, reader)
+
+		/* facility 16(local0) * 8 + info(6)
+		 */
+		if !strings.HasPrefix(frame, "<134>1 ") {
+			t.Errorf("frame %q, want <134>1 prefix", frame)
+		}
+		if !strings.Contains(frame, ` host replayer `) {
+			t.Errorf("frame %q, want hostname and app name", frame)
+		}
+		if !strings.Contains(frame, `[result@32473 test="test \"1\"" rid="a\\b\]"]`) {
+			t.Errorf("frame %q, want escaped structured data", frame)
+		}
+		if !strings.HasSuffix(frame, "] "+msg) {
+			t.Errorf("frame %q, want %q message", frame, msg)
+		}
+	}
+}
+
+/* comment 없는 check step 은 check condition 으로 전송
+ */
+func TestSyslogStepName(t *testing.T) {
+	reporter, serverConn := newTestSyslogReporter(t)
+
+	rcdresult, err := NewRecordResult(0, "syslog_test", false, "")
+	if err != nil {
+		t.Fatal(err.ToString(false))
+	}
+	rcdresult.SetReporter(reporter)
+
+	chkResult, err := NewCheckResult("*", "", false, "")
+	if err != nil {
+		t.Fatal(err.ToString(false))
+	}
+	step, err := NewStep(chkResult)
+	if err != nil {
+		t.Fatal(err.ToString(false))
+	}
+	rcdresult.AddStep(step)
+
+	chkResult.SetResult(constdef.FAIL)
+	chkResult.SetInfo("", "", "", []string{}, -1, "check a == 1")
+
+	serverConn.SetReadDeadline(time.Now().Add(time.Second * 5))
+	reader := bufio.NewReader(serverConn)
+	readOctetFrame(t, reader) /* RECORD_START */
+
+	frame := readOctetFrame(t, reader)
+	if !strings.HasSuffix(frame, "] check a == 1 -> fail") {
+		t.Errorf("frame %q, want check condition", frame)
+	}
+}
+
+/* report server 가 읽지 않아도 write timeout 후 전송 중단
+ */
+func TestSyslogWriteTimeout(t *testing.T) {
+	timeout := SYSLOG_WRITE_TIMEOUT
+	SYSLOG_WRITE_TIMEOUT = time.Millisecond * 100
+	defer func() { SYSLOG_WRITE_TIMEOUT = timeout }()
+
+	reporter, _ := newTestSyslogReporter(t)
+
+	msg := strings.Repeat("x", 1024*1024)
+	done := make(chan bool)
+	go func() {
+		for i := 0; i < 64 && reporter.conn != nil; i++ {
+			reporter.Send(SYSLOG_SEVERITY_INFO, "STEP", []string{}, msg)
+		}
+		done <- true
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second * 10):
+		t.Fatal("syslog send is blocked")
+	}
+
+	if reporter.conn != nil {
+		t.Error("write timeout, want closed connection")
+	}
+}
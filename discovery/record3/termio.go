@@ -42,7 +42,7 @@ func NewTermIO(proc *proc.PtyProcess, outputFilter, inputFilter []FilterInterfac
 		Proc: proc,
 
 		InputChannel:  make(chan string),
-		OutputChannel: proc.OutputChannel,
+		OutputChannel: proc.EnableOutputChannel(),
 
 		OutputFilter: outputFilter,
 		InputFilter:  inputFilter,
@@ -158,6 +158,53 @@ func NewVariableMap() VariableMap {
 	return make(VariableMap)
 }
 
+/* parallel branch 용 복사, list, map 값도 복사하여 branch 간 공유하지 않음
+ */
+func (self VariableMap) Copy() VariableMap {
+	varmap := NewVariableMap()
+	for name, variable := range self {
+		if variable == nil {
+			continue
+		}
+		varmap[name] = &Variable{
+			Name:     variable.Name,
+			Value:    copyValue(variable.Value),
+			LoadPath: variable.LoadPath,
+		}
+	}
+	return varmap
+}
+
+func copyValue(value Void) Void {
+	switch value.(type) {
+	case []Void:
+		list := []Void{}
+		for _, data := range value.([]Void) {
+			list = append(list, copyValue(data))
+		}
+		return list
+	case map[Void]Void:
+		res := make(map[Void]Void)
+		for key, data := range value.(map[Void]Void) {
+			res[key] = copyValue(data)
+		}
+		return res
+	case map[string]interface{}:
+		res := make(map[string]interface{})
+		for key, data := range value.(map[string]interface{}) {
+			res[key] = copyValue(data)
+		}
+		return res
+	case []interface{}:
+		list := []interface{}{}
+		for _, data := range value.([]interface{}) {
+			list = append(list, copyValue(data))
+		}
+		return list
+	}
+	return value
+}
+
 func (self VariableMap) SetValue(variable *Variable) *errors.Error {
 	if variable == nil {
 		return errors.New("Invalid arguments")
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"discovery/config"
+	"discovery/constdef"
+	"discovery/errors"
+	"discovery/fmt"
+	"discovery/record3"
+	"flag"
+	"os"
+)
+
+type arg struct {
+	ResultPath string
+}
+
+func parseArg() (*arg, *errors.Error) {
+	resultPtr := flag.String("result", "", "result timestamp, result directory or results.json path")
+
+	flag.Parse()
+
+	if len(*resultPtr) == 0 {
+		return nil, errors.New("Invalid -result arguments")
+	}
+
+	arg1 := arg{
+		ResultPath: *resultPtr,
+	}
+
+	return &arg1, nil
+}
+
+func help() {
+	fmt.Println("Report")
+	fmt.Println("  -result result timestamp, result directory or results.json path")
+	fmt.Println(`ex) report -result 20230101120000`)
+	fmt.Println(`ex) report -result /opt/contents/results/20230101120000/results.json`)
+}
+
+func main() {
+	arg, err := parseArg()
+	if err != nil {
+		help()
+		fmt.Fprintln(os.Stderr, "ERR:", err.ToString(constdef.DEBUG))
+		os.Exit(1)
+	}
+
+	config.Version()
+
+	path, err := record3.GetResultJsonPath(arg.ResultPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "ERR:", err.ToString(constdef.DEBUG))
+		os.Exit(1)
+	}
+
+	result, err := record3.LoadResult(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "ERR:", err.ToString(constdef.DEBUG))
+		os.Exit(1)
+	}
+
+	err = result.WriteHtml()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "ERR:", err.ToString(constdef.DEBUG))
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"discovery/config"
+	"discovery/constdef"
+	"discovery/errors"
+	"discovery/fmt"
+	"discovery/record3"
+	"flag"
+	"os"
+)
+
+type arg struct {
+	OldResult        string
+	NewResult        string
+	RuntimeThreshold float64
+	RuntimeMinimum   int64
+	FailOnRegression bool
+}
+
+func parseArg() (*arg, *errors.Error) {
+	runtimePtr := flag.Float64("runtime", 50, "run time change percent to report, 0 is disable")
+	runtimeMinPtr := flag.Int64("runtimemin", 1000, "minimum run time change(ms) to report")
+	failPtr := flag.Bool("failonregression", false, "exit 2 when newly failing steps exist")
+
+	flag.Parse()
+
+	if flag.NArg() != 2 {
+		return nil, errors.New("Invalid arguments, old and new result are required")
+	}
+
+	arg1 := arg{
+		OldResult:        flag.Arg(0),
+		NewResult:        flag.Arg(1),
+		RuntimeThreshold: *runtimePtr,
+		RuntimeMinimum:   *runtimeMinPtr,
+		FailOnRegression: *failPtr,
+	}
+
+	return &arg1, nil
+}
+
+func help() {
+	fmt.Println("Resultdiff")
+	fmt.Println("  resultdiff [options] old_result new_result")
+	fmt.Println("  result is timestamp, result directory or results.json path")
+	fmt.Println("  -runtime run time change percent to report, 0 is disable, default 50")
+	fmt.Println("  -runtimemin minimum run time change(ms) to report, default 1000")
+	fmt.Println("  -failonregression exit 2 when newly failing steps exist")
+	fmt.Println(`ex) resultdiff 20230101120000 20230102120000`)
+	fmt.Println(`ex) resultdiff -failonregression -runtime 0 20230101120000 20230102120000`)
+}
+
+func loadResult(resultPath string) (*record3.Result, *errors.Error) {
+	path, err := record3.GetResultJsonPath(resultPath)
+	if err != nil {
+		return nil, err
+	}
+	return record3.LoadResult(path)
+}
+
+func main() {
+	arg, err := parseArg()
+	if err != nil {
+		help()
+		fmt.Fprintln(os.Stderr, "ERR:", err.ToString(constdef.DEBUG))
+		os.Exit(1)
+	}
+
+	config.Version()
+
+	oldResult, err := loadResult(arg.OldResult)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "ERR:", err.ToString(constdef.DEBUG))
+		os.Exit(1)
+	}
+
+	newResult, err := loadResult(arg.NewResult)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "ERR:", err.ToString(constdef.DEBUG))
+		os.Exit(1)
+	}
+
+	diff, err := record3.NewResultDiff(oldResult, newResult, arg.RuntimeThreshold, arg.RuntimeMinimum)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "ERR:", err.ToString(constdef.DEBUG))
+		os.Exit(1)
+	}
+
+	diff.Print()
+
+	if arg.FailOnRegression && diff.HasRegression() {
+		fmt.Fprintln(os.Stderr, "ERR: regression detected")
+		os.Exit(2)
+	}
+}
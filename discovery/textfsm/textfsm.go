@@ -0,0 +1,561 @@
+package textfsm
+
+import (
+	"discovery/errors"
+	"discovery/fmt"
+	"regexp"
+	"strings"
+)
+
+/* TextFSM 호환 template 으로 CLI output 을 record(map) list 로 변환
+ *
+ * Value [Filldown,Key,Required,List,Fillup] NAME (regex)
+ *
+ * Start
+ *   ^regex ${NAME} -> [Next|Continue|Error][.Record|.NoRecord|.Clear|.Clearall] [NewState]
+ */
+
+const (
+	OPTION_FILLDOWN = "Filldown"
+	OPTION_KEY      = "Key"
+	OPTION_REQUIRED = "Required"
+	OPTION_LIST     = "List"
+	OPTION_FILLUP   = "Fillup"
+)
+
+const (
+	LINE_OP_NEXT     = "Next"
+	LINE_OP_CONTINUE = "Continue"
+	LINE_OP_ERROR    = "Error"
+)
+
+const (
+	RECORD_OP_NORECORD = "NoRecord"
+	RECORD_OP_RECORD   = "Record"
+	RECORD_OP_CLEAR    = "Clear"
+	RECORD_OP_CLEARALL = "Clearall"
+)
+
+const (
+	STATE_START = "Start"
+	STATE_END   = "End"
+	STATE_EOF   = "EOF"
+)
+
+var valueOptions = []string{OPTION_FILLDOWN, OPTION_KEY, OPTION_REQUIRED, OPTION_LIST, OPTION_FILLUP}
+
+var stateNameRe = regexp.MustCompile(`^\w+$`)
+var matchActionRe = regexp.MustCompile(`^(.*)(\s->(.*))$`)
+var actionRe = regexp.MustCompile(`^(?:(?:(Next|Continue|Error)(?:\.(Clearall|Clear|Record|NoRecord))?|(Clearall|Clear|Record|NoRecord))(?:\s+(\w+|".*"))?|(\w+))$`)
+var templateVarRe = regexp.MustCompile(`\$(?:\$|\{(\w+)\}|(\w+))`)
+
+/* template Value 정의
+ */
+type Value struct {
+	Name     string
+	Regex    string
+	Options  []string
+	template string
+}
+
+func (self *Value) HasOption(option string) bool {
+	for _, e := range self.Options {
+		if e == option {
+			return true
+		}
+	}
+	return false
+}
+
+/* state 의 rule 정의
+ */
+type Rule struct {
+	Match       string
+	LineOp      string
+	RecordOp    string
+	NewState    string
+	ErrorMsg    string
+	LineNum     int
+	regex       *regexp.Regexp
+	valueGroups map[string]int
+}
+
+type Template struct {
+	Values     []*Value
+	States     map[string][]*Rule
+	StateOrder []string
+}
+
+func NewTemplate(text string) (*Template, *errors.Error) {
+	template := Template{
+		States: make(map[string][]*Rule),
+	}
+
+	lines := strings.Split(strings.Replace(text, "\r\n", "\n", -1), "\n")
+
+	lineNum, err := template.parseValues(lines)
+	if err != nil {
+		return nil, err
+	}
+
+	err = template.parseStates(lines, lineNum)
+	if err != nil {
+		return nil, err
+	}
+
+	err = template.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return &template, nil
+}
+
+func isCommentLine(line string) bool {
+	return strings.HasPrefix(strings.TrimSpace(line), "#")
+}
+
+/* 첫번째 빈 line 까지 Value 정의
+ */
+func (self *Template) parseValues(lines []string) (int, *errors.Error) {
+	for i, line := range lines {
+		if len(strings.TrimSpace(line)) == 0 {
+			return i + 1, nil
+		}
+
+		if isCommentLine(line) {
+			continue
+		}
+
+		if !strings.HasPrefix(line, "Value ") {
+			return 0, errors.New(fmt.Sprintf("line %d, expect Value definition, %s", i+1, line))
+		}
+
+		value, err := newValue(line)
+		if err != nil {
+			return 0, err.AddMsg(fmt.Sprintf("line %d", i+1))
+		}
+
+		for _, v := range self.Values {
+			if v.Name == value.Name {
+				return 0, errors.New(fmt.Sprintf("line %d, duplicate Value name, %s", i+1, value.Name))
+			}
+		}
+
+		self.Values = append(self.Values, value)
+	}
+
+	return len(lines), nil
+}
+
+func newValue(line string) (*Value, *errors.Error) {
+	tokens := strings.Split(line, " ")
+	if len(tokens) < 3 {
+		return nil, errors.New("Value needs at least name and regex")
+	}
+
+	value := Value{}
+
+	/* 세번째 token 이 ( 로 시작하지 않으면 option 이 있음
+	 */
+	if !strings.HasPrefix(tokens[2], "(") {
+		if len(tokens) < 4 {
+			return nil, errors.New("Value needs at least name and regex")
+		}
+
+		for _, option := range strings.Split(tokens[1], ",") {
+			valid := false
+			for _, e := range valueOptions {
+				if option == e {
+					valid = true
+					break
+				}
+			}
+			if !valid {
+				return nil, errors.New(fmt.Sprintf("invalid Value option, %s", option))
+			}
+			if value.HasOption(option) {
+				return nil, errors.New(fmt.Sprintf("duplicate Value option, %s", option))
+			}
+			value.Options = append(value.Options, option)
+		}
+
+		value.Name = tokens[2]
+		value.Regex = strings.Join(tokens[3:], " ")
+	} else {
+		value.Name = tokens[1]
+		value.Regex = strings.Join(tokens[2:], " ")
+	}
+
+	if !stateNameRe.MatchString(value.Name) {
+		return nil, errors.New(fmt.Sprintf("invalid Value name, %s", value.Name))
+	}
+
+	if !strings.HasPrefix(value.Regex, "(") || !strings.HasSuffix(value.Regex, ")") {
+		return nil, errors.New(fmt.Sprintf("Value %s regex have to be contained within a ()", value.Name))
+	}
+
+	_, oserr := regexp.Compile(value.Regex)
+	if oserr != nil {
+		return nil, errors.New(fmt.Sprintf("Value %s, %s", value.Name, oserr))
+	}
+
+	value.template = fmt.Sprintf("(?P<%s>%s", value.Name, value.Regex[1:])
+
+	return &value, nil
+}
+
+/* state 이름 line 이후 빈 line 까지 rule 정의
+ */
+func (self *Template) parseStates(lines []string, lineNum int) *errors.Error {
+	stateName := ""
+
+	for i := lineNum; i < len(lines); i++ {
+		line := lines[i]
+
+		if isCommentLine(line) {
+			continue
+		}
+
+		if len(strings.TrimSpace(line)) == 0 {
+			stateName = ""
+			continue
+		}
+
+		if len(stateName) == 0 {
+			name := strings.TrimSpace(line)
+			if line != name || !stateNameRe.MatchString(name) {
+				return errors.New(fmt.Sprintf("line %d, invalid state name, %s", i+1, line))
+			}
+
+			if _, ok := self.States[name]; ok {
+				return errors.New(fmt.Sprintf("line %d, duplicate state, %s", i+1, name))
+			}
+
+			self.States[name] = []*Rule{}
+			self.StateOrder = append(self.StateOrder, name)
+			stateName = name
+			continue
+		}
+
+		if line == strings.TrimLeft(line, " \t") || !strings.HasPrefix(strings.TrimSpace(line), "^") {
+			return errors.New(fmt.Sprintf("line %d, rule have to start with white space and ^, %s", i+1, line))
+		}
+
+		rule, err := self.newRule(strings.TrimSpace(line), i+1)
+		if err != nil {
+			return err.AddMsg(fmt.Sprintf("line %d", i+1))
+		}
+
+		self.States[stateName] = append(self.States[stateName], rule)
+	}
+
+	return nil
+}
+
+func (self *Template) newRule(line string, lineNum int) (*Rule, *errors.Error) {
+	rule := Rule{
+		Match:    line,
+		LineOp:   LINE_OP_NEXT,
+		RecordOp: RECORD_OP_NORECORD,
+		LineNum:  lineNum,
+	}
+
+	if m := matchActionRe.FindStringSubmatch(line); m != nil {
+		rule.Match = strings.TrimSpace(m[1])
+
+		action := strings.TrimSpace(m[3])
+		am := actionRe.FindStringSubmatch(action)
+		if am == nil {
+			return nil, errors.New(fmt.Sprintf("invalid rule action, %s", action))
+		}
+
+		if len(am[1]) > 0 {
+			rule.LineOp = am[1]
+		}
+		if len(am[2]) > 0 {
+			rule.RecordOp = am[2]
+		}
+		if len(am[3]) > 0 {
+			rule.RecordOp = am[3]
+		}
+
+		/* Error 의 경우 state 대신 error message 사용 가능
+		 */
+		if len(am[5]) > 0 {
+			rule.NewState = am[5]
+		} else if len(am[4]) > 0 {
+			if rule.LineOp == LINE_OP_ERROR {
+				rule.ErrorMsg = strings.Trim(am[4], `"`)
+			} else if strings.HasPrefix(am[4], `"`) {
+				return nil, errors.New(fmt.Sprintf("invalid new state, %s", am[4]))
+			} else {
+				rule.NewState = am[4]
+			}
+		}
+
+		if rule.LineOp == LINE_OP_CONTINUE && len(rule.NewState) > 0 {
+			return nil, errors.New("Continue can't change state")
+		}
+	}
+
+	/* ${NAME}, $NAME 을 Value regex 로 치환, $$ 는 $
+	 */
+	var substErr *errors.Error
+	regex := templateVarRe.ReplaceAllStringFunc(rule.Match, func(s string) string {
+		if s == "$$" {
+			return "$"
+		}
+
+		m := templateVarRe.FindStringSubmatch(s)
+		name := m[1]
+		if len(name) == 0 {
+			name = m[2]
+		}
+
+		value := self.GetValue(name)
+		if value == nil {
+			substErr = errors.New(fmt.Sprintf("unknown Value, %s", name))
+			return s
+		}
+		return value.template
+	})
+	if substErr != nil {
+		return nil, substErr
+	}
+
+	re, oserr := regexp.Compile(regex)
+	if oserr != nil {
+		return nil, errors.New(fmt.Sprintf("%s", oserr))
+	}
+	rule.regex = re
+
+	rule.valueGroups = make(map[string]int)
+	for i, name := range re.SubexpNames() {
+		if i > 0 && self.GetValue(name) != nil {
+			rule.valueGroups[name] = i
+		}
+	}
+
+	return &rule, nil
+}
+
+func (self *Template) validate() *errors.Error {
+	if len(self.Values) == 0 {
+		return errors.New("template has no Value")
+	}
+
+	if _, ok := self.States[STATE_START]; !ok {
+		return errors.New("template has no Start state")
+	}
+
+	if rules, ok := self.States[STATE_END]; ok && len(rules) > 0 {
+		return errors.New("End state have to be empty")
+	}
+
+	if rules, ok := self.States[STATE_EOF]; ok && len(rules) > 0 {
+		return errors.New("EOF state have to be empty")
+	}
+
+	for _, name := range self.StateOrder {
+		for _, rule := range self.States[name] {
+			if len(rule.NewState) == 0 || rule.NewState == STATE_END || rule.NewState == STATE_EOF {
+				continue
+			}
+			if _, ok := self.States[rule.NewState]; !ok {
+				return errors.New(fmt.Sprintf("line %d, unknown state, %s", rule.LineNum, rule.NewState))
+			}
+		}
+	}
+
+	return nil
+}
+
+func (self *Template) GetValue(name string) *Value {
+	for _, value := range self.Values {
+		if value.Name == name {
+			return value
+		}
+	}
+	return nil
+}
+
+/* parsing 중 value 상태, template 은 재사용 가능하도록 parse 마다 생성
+ */
+type parser struct {
+	template *Template
+	current  []interface{} // string 또는 []string, 값 없으면 nil
+	results  [][]interface{}
+}
+
+/* text 를 parsing 하여 Value name - 값 map list 리턴
+ * List option Value 는 []string, 나머지는 string
+ */
+func (self *Template) Parse(text string) ([]map[string]interface{}, *errors.Error) {
+	p := parser{
+		template: self,
+		current:  make([]interface{}, len(self.Values)),
+	}
+
+	stateName := STATE_START
+	lines := strings.Split(text, "\n")
+	if len(lines) > 0 && len(lines[len(lines)-1]) == 0 {
+		lines = lines[:len(lines)-1]
+	}
+
+	for _, line := range lines {
+		line = strings.TrimRight(line, "\r")
+
+		newState, err := p.checkLine(stateName, line)
+		if err != nil {
+			return nil, err
+		}
+		stateName = newState
+
+		if stateName == STATE_END || stateName == STATE_EOF {
+			break
+		}
+	}
+
+	/* EOF state 가 정의되어 있지 않으면 마지막 record 저장
+	 */
+	if _, ok := self.States[STATE_EOF]; !ok && stateName != STATE_END {
+		p.appendRecord()
+	}
+
+	output := []map[string]interface{}{}
+	for _, result := range p.results {
+		record := make(map[string]interface{})
+		for i, value := range self.Values {
+			if result[i] == nil {
+				if value.HasOption(OPTION_LIST) {
+					record[value.Name] = []string{}
+				} else {
+					record[value.Name] = ""
+				}
+			} else {
+				record[value.Name] = result[i]
+			}
+		}
+		output = append(output, record)
+	}
+
+	return output, nil
+}
+
+func (self *parser) checkLine(stateName string, line string) (string, *errors.Error) {
+	for _, rule := range self.template.States[stateName] {
+		m := rule.regex.FindStringSubmatchIndex(line)
+		if m == nil {
+			continue
+		}
+
+		for name, idx := range rule.valueGroups {
+			if m[2*idx] < 0 {
+				continue
+			}
+			self.assignValue(name, line[m[2*idx]:m[2*idx+1]])
+		}
+
+		switch rule.RecordOp {
+		case RECORD_OP_RECORD:
+			self.appendRecord()
+		case RECORD_OP_CLEAR:
+			self.clearRecord(false)
+		case RECORD_OP_CLEARALL:
+			self.clearRecord(true)
+		}
+
+		switch rule.LineOp {
+		case LINE_OP_ERROR:
+			msg := rule.ErrorMsg
+			if len(msg) == 0 {
+				msg = "rule error"
+			}
+			return stateName, errors.New(fmt.Sprintf("%s, template line %d, input line: %s", msg, rule.LineNum, line))
+		case LINE_OP_CONTINUE:
+			continue
+		}
+
+		if len(rule.NewState) > 0 {
+			return rule.NewState, nil
+		}
+		return stateName, nil
+	}
+
+	return stateName, nil
+}
+
+func (self *parser) assignValue(name string, data string) {
+	for i, value := range self.template.Values {
+		if value.Name != name {
+			continue
+		}
+
+		if value.HasOption(OPTION_LIST) {
+			list, _ := self.current[i].([]string)
+			self.current[i] = append(list, data)
+		} else {
+			self.current[i] = data
+		}
+
+		/* Fillup 은 이전 record 중 값이 없는 record 를 위로 채움
+		 */
+		if value.HasOption(OPTION_FILLUP) {
+			for j := len(self.results) - 1; j >= 0; j-- {
+				if !isEmpty(self.results[j][i]) {
+					break
+				}
+				self.results[j][i] = self.current[i]
+			}
+		}
+		return
+	}
+}
+
+func isEmpty(data interface{}) bool {
+	switch data.(type) {
+	case nil:
+		return true
+	case string:
+		return len(data.(string)) == 0
+	case []string:
+		return len(data.([]string)) == 0
+	}
+	return false
+}
+
+func (self *parser) appendRecord() {
+	empty := true
+
+	for i, value := range self.template.Values {
+		if value.HasOption(OPTION_REQUIRED) && isEmpty(self.current[i]) {
+			self.clearRecord(false)
+			return
+		}
+		if !isEmpty(self.current[i]) {
+			empty = false
+		}
+	}
+
+	if empty {
+		return
+	}
+
+	record := make([]interface{}, len(self.current))
+	copy(record, self.current)
+	self.results = append(self.results, record)
+
+	self.clearRecord(false)
+}
+
+/* Filldown Value 는 clearall 에서만 삭제
+ */
+func (self *parser) clearRecord(allFlag bool) {
+	for i, value := range self.template.Values {
+		if !allFlag && value.HasOption(OPTION_FILLDOWN) {
+			continue
+		}
+		self.current[i] = nil
+	}
+}
@@ -44,17 +44,15 @@ func SetFileLock(fp *os.File) *errors.Error {
 	if goerr != nil {
 		return errors.New(fmt.Sprintf("%s", goerr))
 	}
-	fmt.Println("locked...")
 	return nil
 }
 
 func SetFileUnlock(fp *os.File) *errors.Error {
 	unlock := flock
 	unlock.Type = syscall.F_UNLCK
-	goerr := syscall.FcntlFlock(uintptr(fp.Fd()), syscall.F_SETLK, &flock)
+	goerr := syscall.FcntlFlock(uintptr(fp.Fd()), syscall.F_SETLK, &unlock)
 	if goerr != nil {
 		return errors.New(fmt.Sprintf("%s", goerr))
 	}
-	fmt.Println("unlocked...")
 	return nil
 }
@@ -69,13 +69,17 @@ func ParseEnvId(rid string) (string, []string, *errors.Error) {
 }
 
 func PrintResult(resultcode uint8) {
+	fmt.Printf("%s", ResultString(resultcode))
+}
+
+func ResultString(resultcode uint8) string {
 	switch resultcode {
 	case constdef.SUCCESS:
-		fmt.Printf(" %s-> SUCCESS%s", constdef.ANSI_GREEN_BOLD, constdef.ANSI_END)
+		return fmt.Sprintf(" %s-> SUCCESS%s", constdef.ANSI_GREEN_BOLD, constdef.ANSI_END)
 	case constdef.FAIL:
-		fmt.Printf(" %s-> FAIL%s", constdef.ANSI_RED_BOLD, constdef.ANSI_END)
+		return fmt.Sprintf(" %s-> FAIL%s", constdef.ANSI_RED_BOLD, constdef.ANSI_END)
 	default:
-		fmt.Printf(" %s-> N/A%s", constdef.ANSI_RED_BOLD, constdef.ANSI_END)
+		return fmt.Sprintf(" %s-> N/A%s", constdef.ANSI_RED_BOLD, constdef.ANSI_END)
 	}
 }
 